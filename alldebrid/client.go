@@ -11,6 +11,8 @@ import (
 
 	"github.com/tidwall/gjson"
 	"go.uber.org/zap"
+
+	debrid "github.com/deflix-tv/go-debrid"
 )
 
 var zapDebridService = zap.String("debridService", "AllDebrid")
@@ -123,6 +125,10 @@ func (c *Client) UploadMagnet(ctx context.Context, magnet string) (Magnet, error
 		errorCode := gjson.GetBytes(resBytes, "error.message").String()
 		return Magnet{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
 	}
+	// Errors for single magnets are part of the successful response
+	if errorCode := gjson.GetBytes(resBytes, "data.magnets.0.error.message"); errorCode.Exists() {
+		return Magnet{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode.String())
+	}
 	magnetJSON := gjson.GetBytes(resBytes, "data.magnets.0").Raw
 	m := Magnet{}
 	if err = json.Unmarshal([]byte(magnetJSON), &m); err != nil {
@@ -133,6 +139,82 @@ func (c *Client) UploadMagnet(ctx context.Context, magnet string) (Magnet, error
 	return m, nil
 }
 
+// MaxMagnetsPerUpload is the maximum number of magnets that UploadMagnets sends per request.
+// Larger batches are split into multiple requests.
+const MaxMagnetsPerUpload = 100
+
+// UploadMagnets adds multiple torrents to AllDebrid via magnet URL.
+// The magnet strings can actually also be hashes.
+// The returned results are in the same order as the magnets. Magnets that couldn't be added have their Error set.
+// Their hashes are normalized (see debrid.NormalizeInfoHash), so they can be matched with the hashes of other methods.
+// If a request fails, or AllDebrid doesn't return exactly one result per magnet, the results of the previous requests are returned along with an error.
+func (c *Client) UploadMagnets(ctx context.Context, magnets ...string) ([]MagnetUpload, error) {
+	c.logger.Debug("Uploading magnets...", zap.Int("magnetCount", len(magnets)), zapDebridService)
+
+	uploads := make([]MagnetUpload, 0, len(magnets))
+	for start := 0; start < len(magnets); start += MaxMagnetsPerUpload {
+		end := start + MaxMagnetsPerUpload
+		if end > len(magnets) {
+			end = len(magnets)
+		}
+		data := url.Values{"magnets[]": magnets[start:end]}
+		resBytes, err := c.post(ctx, c.opts.BaseURL+"/magnet/upload", data)
+		if err != nil {
+			return uploads, fmt.Errorf("couldn't upload magnets: %w", err)
+		}
+		if gjson.GetBytes(resBytes, "status").String() != "success" {
+			errorCode := gjson.GetBytes(resBytes, "error.message").String()
+			return uploads, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+		}
+		magnetsJSON := gjson.GetBytes(resBytes, "data.magnets").Raw
+		batch := []MagnetUpload{}
+		if err = json.Unmarshal([]byte(magnetsJSON), &batch); err != nil {
+			return uploads, fmt.Errorf("couldn't unmarshal magnets: %w", err)
+		}
+		// The results can only be matched with the magnets by their position
+		if len(batch) != end-start {
+			return uploads, fmt.Errorf("got %v results from AllDebrid for %v magnets", len(batch), end-start)
+		}
+		for i := range batch {
+			if hash, err := debrid.NormalizeInfoHash(batch[i].Hash); err == nil {
+				batch[i].Hash = hash
+			}
+		}
+		uploads = append(uploads, batch...)
+	}
+
+	c.logger.Debug("Uploaded magnets", zap.String("magnets", fmt.Sprintf("%+v", uploads)), zapDebridService)
+	return uploads, nil
+}
+
+// UploadTorrent adds a torrent to AllDebrid via the content of a .torrent file.
+// The file name is only used as name of the uploaded file.
+// The Magnet field of the returned Magnet is empty.
+func (c *Client) UploadTorrent(ctx context.Context, fileName string, torrent []byte) (Magnet, error) {
+	c.logger.Debug("Uploading torrent...", zapDebridService)
+
+	resBytes, err := c.postFile(ctx, c.opts.BaseURL+"/magnet/upload/file", "files[]", fileName, torrent)
+	if err != nil {
+		return Magnet{}, fmt.Errorf("couldn't upload torrent: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return Magnet{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	// Errors for single files are part of the successful response
+	if errorCode := gjson.GetBytes(resBytes, "data.files.0.error.message"); errorCode.Exists() {
+		return Magnet{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode.String())
+	}
+	magnetJSON := gjson.GetBytes(resBytes, "data.files.0").Raw
+	m := Magnet{}
+	if err = json.Unmarshal([]byte(magnetJSON), &m); err != nil {
+		return Magnet{}, fmt.Errorf("couldn't unmarshal magnet: %w", err)
+	}
+
+	c.logger.Debug("Uploaded torrent", zap.String("magnet", fmt.Sprintf("%+v", m)), zapDebridService)
+	return m, nil
+}
+
 // GetStatus fetches and returns the status of all torrents that were added to AllDebrid for a specific user.
 // The ID must be the one returned from AllDebrid when adding the torrent to AllDebrid.
 func (c *Client) GetStatus(ctx context.Context) ([]Status, error) {
@@ -151,6 +233,9 @@ func (c *Client) GetStatus(ctx context.Context) ([]Status, error) {
 	if err = json.Unmarshal([]byte(statusJSON), &status); err != nil {
 		return nil, fmt.Errorf("couldn't unmarshal status: %w", err)
 	}
+	for _, st := range status {
+		c.logUnparsedFiles(st)
+	}
 
 	c.logger.Debug("Got status", zap.String("status", fmt.Sprintf("%+v", status)), zapDebridService)
 	return status, nil
@@ -174,11 +259,35 @@ func (c *Client) GetStatusByID(ctx context.Context, id int) (Status, error) {
 	if err = json.Unmarshal([]byte(statusJSON), &status); err != nil {
 		return Status{}, fmt.Errorf("couldn't unmarshal status by ID: %w", err)
 	}
+	c.logUnparsedFiles(status)
 
 	c.logger.Debug("Got status by ID", zap.String("status", fmt.Sprintf("%+v", status)), zapDebridService)
 	return status, nil
 }
 
+// logUnparsedFiles logs the links of the status whose files couldn't be parsed into a tree when unmarshalling the status.
+func (c *Client) logUnparsedFiles(status Status) {
+	for _, link := range status.Links {
+		if link.Tree != nil || len(link.Files) == 0 {
+			continue
+		}
+		if _, err := ParseFiles(link.Files, status.Version); err != nil {
+			c.logger.Error("Couldn't parse files of link", zap.Error(err), zap.Int("magnetID", status.ID), zap.String("link", link.Link), zapDebridService)
+		}
+	}
+}
+
+// logUnparsedInstantFiles logs the cached files of a magnet if they couldn't be parsed into a tree when unmarshalling the instant availability.
+func (c *Client) logUnparsedInstantFiles(magnet string, filesJSON gjson.Result) {
+	files, ok := filesJSON.Value().([]interface{})
+	if !ok || len(files) == 0 {
+		return
+	}
+	if _, err := ParseFiles(files, 0); err != nil {
+		c.logger.Error("Couldn't parse cached files of magnet", zap.Error(err), zap.String("magnet", magnet), zapDebridService)
+	}
+}
+
 // DeleteMagnet deletes a magnet from the user's magnets.
 // The ID must be the one returned from AllDebrid when adding the magnet or getting status info about it.
 func (c *Client) DeleteMagnet(ctx context.Context, id int) error {
@@ -197,10 +306,43 @@ func (c *Client) DeleteMagnet(ctx context.Context, id int) error {
 	return nil
 }
 
+// RestartMagnet restarts the download of a magnet that failed to download.
+// The ID must be the one returned from AllDebrid when adding the magnet or getting status info about it.
+func (c *Client) RestartMagnet(ctx context.Context, id int) error {
+	c.logger.Debug("Restarting magnet...", zapDebridService)
+
+	if err := c.getSuccess(ctx, c.opts.BaseURL+"/magnet/restart?id="+strconv.Itoa(id)); err != nil {
+		return fmt.Errorf("couldn't restart magnet: %w", err)
+	}
+
+	c.logger.Debug("Restarted magnet", zapDebridService)
+	return nil
+}
+
 // GetInstantAvailability fetches and returns info about the instant availability of a torrent.
 // The hashes can actually also be magnet URLs.
-// The returned map contains the hashes / magnet URLs of the torrents that are instantly available.
+// The returned map contains the normalized info hashes (see debrid.NormalizeInfoHash) of the torrents that are instantly available.
+// Use GetInstantAvailabilityDetails to also get the cached files and per-magnet errors.
 func (c *Client) GetInstantAvailability(ctx context.Context, hashes ...string) (map[string]struct{}, error) {
+	details, err := c.GetInstantAvailabilityDetails(ctx, hashes...)
+	if err != nil {
+		return nil, err
+	}
+	availabilities := make(map[string]struct{}, len(details))
+	for hash, availability := range details {
+		if availability.Instant {
+			availabilities[hash] = struct{}{}
+		}
+	}
+	return availabilities, nil
+}
+
+// GetInstantAvailabilityDetails fetches and returns info about the instant availability of a torrent, including its cached files.
+// The hashes can actually also be magnet URLs.
+// The returned map contains an element for each magnet in the response, also for the ones that aren't instantly available or led to an error.
+// Its keys are normalized info hashes (see debrid.NormalizeInfoHash). Only if AllDebrid returns an error for a magnet
+// that doesn't contain a valid info hash, the key is the magnet as it was sent.
+func (c *Client) GetInstantAvailabilityDetails(ctx context.Context, hashes ...string) (map[string]InstantAvailability, error) {
 	c.logger.Debug("Getting instant availability...", zapDebridService)
 
 	data := url.Values{"magnets[]": hashes}
@@ -212,13 +354,31 @@ func (c *Client) GetInstantAvailability(ctx context.Context, hashes ...string) (
 		errorCode := gjson.GetBytes(resBytes, "error.message").String()
 		return nil, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
 	}
-	availabilities := make(map[string]struct{}, len(hashes))
-	gjson.GetBytes(resBytes, "data.magnets").ForEach(func(key, value gjson.Result) bool {
-		if value.Get("instant").Bool() {
-			availabilities[value.Get("magnet").String()] = struct{}{}
+	magnetsJSON := gjson.GetBytes(resBytes, "data.magnets")
+	magnets := []InstantAvailability{}
+	if magnetsJSON.Exists() {
+		if err = json.Unmarshal([]byte(magnetsJSON.Raw), &magnets); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal instant availability: %w", err)
 		}
-		return true
-	})
+	}
+	availabilities := make(map[string]InstantAvailability, len(magnets))
+	for i, availability := range magnets {
+		if availability.Files == nil {
+			c.logUnparsedInstantFiles(availability.Magnet, magnetsJSON.Get(strconv.Itoa(i)+".files"))
+		}
+		hash, err := debrid.NormalizeInfoHash(availability.Hash)
+		if err != nil {
+			if hash, err = debrid.ParseInfoHash(availability.Magnet); err != nil {
+				if availability.Error == nil {
+					c.logger.Error("Couldn't normalize available hash", zap.Error(err), zap.String("magnet", availability.Magnet), zapDebridService)
+					continue
+				}
+				hash = availability.Magnet
+			}
+		}
+		availability.Hash = hash
+		availabilities[hash] = availability
+	}
 
 	c.logger.Debug("Got instant availability", zap.String("availabilities", fmt.Sprintf("%+v", availabilities)), zapDebridService)
 	return availabilities, nil
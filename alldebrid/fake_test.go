@@ -0,0 +1,398 @@
+package alldebrid_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+	"github.com/deflix-tv/go-debrid/alldebrid"
+	"github.com/deflix-tv/go-debrid/internal/fakeserver"
+)
+
+// fakeResponse is a canned response of the fake AllDebrid server.
+type fakeResponse = fakeserver.Response
+
+// newFakeClient starts a fake AllDebrid server that responds to requests to paths like "/hosts" with the given responses,
+// and returns a client that sends its requests to it.
+func newFakeClient(t *testing.T, responses map[string]fakeResponse) *alldebrid.Client {
+	server := fakeserver.New(t, fakeserver.ByPath, func(t *testing.T, r *http.Request) {
+		assert.Equal(t, "go-debrid", r.URL.Query().Get("agent"))
+		assert.Equal(t, "123", r.URL.Query().Get("apikey"))
+	}, responses)
+	opts := alldebrid.DefaultClientOpts
+	opts.BaseURL = server.URL
+	return alldebrid.NewClient(opts, "123", nil)
+}
+
+func TestHosts(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/hosts": {Body: `{"status":"success","data":{"hosts":{"uptobox":{"name":"uptobox","type":"premium","domains":["uptobox.com"],"regexps":["uptobox\\.com/[a-z0-9]{12}"],"status":true,"quota":5000,"quotaMax":5000,"quotaType":"traffic"}},` +
+			`"streams":{"youtube":{"name":"youtube","type":"free","domains":["youtube.com"],"status":true}},"redirectors":{}}}`},
+		"/hosts/domains":  {Body: `{"status":"success","data":{"hosts":["uptobox.com"],"streams":["youtube.com"],"redirectors":["adf.ly"]}}`},
+		"/hosts/priority": {Body: `{"status":"success","data":{"hosts":{"uptobox":1,"1fichier":2}}}`},
+	})
+	ctx := context.Background()
+
+	hosts, err := client.GetHosts(ctx)
+	require.NoError(t, err)
+	require.Equal(t, alldebrid.Host{Name: "uptobox", Type: "premium", Domains: []string{"uptobox.com"}, Regexps: []string{`uptobox\.com/[a-z0-9]{12}`}, Status: true, Quota: 5000, QuotaMax: 5000, QuotaType: "traffic"}, hosts.Hosts["uptobox"])
+	require.Equal(t, "free", hosts.Streams["youtube"].Type)
+	require.Empty(t, hosts.Redirectors)
+
+	domains, err := client.GetHostDomains(ctx)
+	require.NoError(t, err)
+	require.Equal(t, alldebrid.HostDomains{Hosts: []string{"uptobox.com"}, Streams: []string{"youtube.com"}, Redirectors: []string{"adf.ly"}}, domains)
+
+	priority, err := client.GetHostPriority(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"uptobox": 1, "1fichier": 2}, priority)
+}
+
+func TestLinks(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/link/infos": {Body: `{"status":"success","data":{"infos":[{"link":"https://uptobox.com/abc","filename":"movie.mkv","size":123,"host":"uptobox","hostDomain":"uptobox.com"},` +
+			`{"link":"https://uptobox.com/def","error":{"code":"LINK_DOWN","message":"This link is not available on the file hoster website"}}]}}`, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, []string{"https://uptobox.com/abc", "https://uptobox.com/def"}, r.URL.Query()["link[]"])
+			assert.Equal(t, "secret", r.URL.Query().Get("password"))
+		}},
+		"/link/redirector": {Body: `{"status":"success","data":{"links":["https://uptobox.com/abc"]}}`, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "https://adf.ly/123", r.URL.Query().Get("link"))
+		}},
+	})
+	ctx := context.Background()
+
+	infos, err := client.GetLinkInfos(ctx, "secret", "https://uptobox.com/abc", "https://uptobox.com/def")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, "movie.mkv", infos[0].Filename)
+	require.Nil(t, infos[0].Error)
+	require.Equal(t, "LINK_DOWN", infos[1].Error.Code)
+
+	links, err := client.GetRedirectorLinks(ctx, "https://adf.ly/123")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://uptobox.com/abc"}, links)
+}
+
+func TestUserLinks(t *testing.T) {
+	success := fakeResponse{Body: `{"status":"success","data":{"message":"Done"}}`}
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/user/links": {Body: `{"status":"success","data":{"links":[{"link":"https://uptobox.com/abc","filename":"movie.mkv","size":123,"date":1604756220,"host":"uptobox"}]}}`},
+		"/user/links/save": {Body: success.Body, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, []string{"https://uptobox.com/abc"}, r.URL.Query()["links[]"])
+		}},
+		"/user/links/delete": {Body: success.Body, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, []string{"https://uptobox.com/abc"}, r.URL.Query()["links[]"])
+		}},
+		// No "links" if the history is disabled
+		"/user/history":        {Body: `{"status":"success","data":{}}`},
+		"/user/history/delete": success,
+		"/user/verif":          {Body: `{"status":"success","data":{"verif":"allowed","resendable":false,"apikey":"KEY"}}`},
+		"/user/verif/resend":   success,
+		"/magnet/restart": {Body: success.Body, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "42", r.URL.Query().Get("id"))
+		}},
+	})
+	ctx := context.Background()
+
+	links, err := client.GetSavedLinks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []alldebrid.SavedLink{{Link: "https://uptobox.com/abc", Filename: "movie.mkv", Size: 123, Date: time.Unix(1604756220, 0), Host: "uptobox"}}, links)
+	require.NoError(t, client.SaveLinks(ctx, "https://uptobox.com/abc"))
+	require.NoError(t, client.DeleteSavedLinks(ctx, "https://uptobox.com/abc"))
+
+	history, err := client.GetHistory(ctx)
+	require.NoError(t, err)
+	require.Empty(t, history)
+	require.NoError(t, client.DeleteHistory(ctx))
+
+	verif, err := client.GetVerif(ctx, "TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, alldebrid.Verif{Verif: "allowed", APIKey: "KEY"}, verif)
+	require.NoError(t, client.ResendVerif(ctx, "TOKEN"))
+
+	require.NoError(t, client.RestartMagnet(ctx, 42))
+}
+
+func TestErrorResponse(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/magnet/restart": {Body: `{"status":"error","error":{"code":"MAGNET_INVALID_ID","message":"This magnet ID does not exists or is invalid"}}`},
+	})
+	err := client.RestartMagnet(context.Background(), 42)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "This magnet ID does not exists or is invalid")
+}
+
+func TestUnlockAndWait(t *testing.T) {
+	unlockBody := `{"status":"success","data":{"link":"","filename":"movie.mkv","host":"uptobox","filesize":123,"id":"ID","delayed":7}}`
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/link/unlock": {Body: unlockBody},
+		"/link/delayed": {Bodies: []string{
+			`{"status":"success","data":{"status":1,"time_left":10}}`,
+			`{"status":"success","data":{"status":1,"time_left":5}}`,
+			`{"status":"success","data":{"status":2,"time_left":0,"link":"https://abc.debrid.it/dl/xyz/movie.mkv"}}`,
+		}, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "7", r.URL.Query().Get("id"))
+		}},
+	})
+	ctx := context.Background()
+
+	dl, err := client.UnlockAndWait(ctx, "https://uptobox.com/abc", time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "https://abc.debrid.it/dl/xyz/movie.mkv", dl.Link)
+	require.Equal(t, "movie.mkv", dl.Filename)
+
+	// Failed generation
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/link/unlock":  {Body: unlockBody},
+		"/link/delayed": {Body: `{"status":"success","data":{"status":3,"time_left":0}}`},
+	})
+	_, err = client.UnlockAndWait(ctx, "https://uptobox.com/abc", time.Millisecond)
+	require.ErrorIs(t, err, alldebrid.ErrorDelayedLinkFailed)
+
+	// Context expiry
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/link/unlock":  {Body: unlockBody},
+		"/link/delayed": {Body: `{"status":"success","data":{"status":1,"time_left":60}}`},
+	})
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = client.UnlockAndWait(ctx, "https://uptobox.com/abc", time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGetStreamingLink(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/link/streaming": {Body: `{"status":"success","data":{"link":"https://abc.debrid.it/dl/xyz/video-720p.mp4","filename":"video-720p.mp4","filesize":500}}`, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "ID", r.URL.Query().Get("id"))
+			assert.Equal(t, "c", r.URL.Query().Get("stream"))
+		}},
+	})
+
+	ctx := context.Background()
+	dl, err := client.GetStreamingLink(ctx, "ID", "c", time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, alldebrid.Download{Link: "https://abc.debrid.it/dl/xyz/video-720p.mp4", Filename: "video-720p.mp4", Filesize: 500}, dl)
+
+	// Delayed stream
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/link/streaming": {Body: `{"status":"success","data":{"link":"","filename":"video-720p.mp4","filesize":500,"delayed":8}}`},
+		"/link/delayed": {Bodies: []string{
+			`{"status":"success","data":{"status":1,"time_left":5}}`,
+			`{"status":"success","data":{"status":2,"time_left":0,"link":"https://abc.debrid.it/dl/xyz/video-720p.mp4"}}`,
+		}, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "8", r.URL.Query().Get("id"))
+		}},
+	})
+	dl, err = client.GetStreamingLink(ctx, "ID", "c", time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "https://abc.debrid.it/dl/xyz/video-720p.mp4", dl.Link)
+	require.Equal(t, "video-720p.mp4", dl.Filename)
+
+	// Failed generation
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/link/streaming": {Body: `{"status":"success","data":{"link":"","filename":"video-720p.mp4","filesize":500,"delayed":8}}`},
+		"/link/delayed":   {Body: `{"status":"success","data":{"status":3,"time_left":0}}`},
+	})
+	_, err = client.GetStreamingLink(ctx, "ID", "c", time.Millisecond)
+	require.ErrorIs(t, err, alldebrid.ErrorDelayedLinkFailed)
+}
+
+func TestGetInstantAvailabilityDetails(t *testing.T) {
+	hash := "50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139"
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/magnet/instant": {Body: `{"status":"success","data":{"magnets":[` +
+			`{"magnet":"50b7dafb7137cbecf045f78e8efbe4ac1a90d139","hash":"50b7dafb7137cbecf045f78e8efbe4ac1a90d139","instant":true,"files":[{"n":"Night of the Living Dead.mp4","s":1234}]},` +
+			`{"magnet":"11EA02584FA6351956F35671962AB46354D99060","hash":"11ea02584fa6351956f35671962ab46354d99060","instant":false},` +
+			`{"magnet":"foo","error":{"code":"MAGNET_INVALID_URI","message":"Magnet URI is invalid"}}]}}`, Check: func(t *testing.T, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			assert.Len(t, r.PostForm["magnets[]"], 3)
+		}},
+	})
+	ctx := context.Background()
+
+	details, err := client.GetInstantAvailabilityDetails(ctx, strings.ToLower(hash), "11EA02584FA6351956F35671962AB46354D99060", "foo")
+	require.NoError(t, err)
+	require.Len(t, details, 3)
+	available := details[hash]
+	require.True(t, available.Instant)
+	require.Equal(t, hash, available.Hash)
+	require.Equal(t, []debrid.File{{Path: "/Night of the Living Dead.mp4", Size: 1234}}, alldebrid.FlattenFiles(available.Files))
+	require.False(t, details["11EA02584FA6351956F35671962AB46354D99060"].Instant)
+	require.Equal(t, "MAGNET_INVALID_URI", details["foo"].Error.Code)
+
+	availabilities, err := client.GetInstantAvailability(ctx, strings.ToLower(hash), "11EA02584FA6351956F35671962AB46354D99060", "foo")
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{hash: {}}, availabilities)
+
+	// Files in an unexpected format don't fail the whole response
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/magnet/instant": {Body: `{"status":"success","data":{"magnets":[` +
+			`{"magnet":"50b7dafb7137cbecf045f78e8efbe4ac1a90d139","hash":"50b7dafb7137cbecf045f78e8efbe4ac1a90d139","instant":true,"files":[42]}]}}`},
+	})
+	details, err = client.GetInstantAvailabilityDetails(ctx, hash)
+	require.NoError(t, err)
+	require.True(t, details[hash].Instant)
+	require.Nil(t, details[hash].Files)
+
+	// No magnets in the response
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/magnet/instant": {Body: `{"status":"success","data":{}}`},
+	})
+	availabilities, err = client.GetInstantAvailability(ctx, hash)
+	require.NoError(t, err)
+	require.Empty(t, availabilities)
+}
+
+func TestUploadMagnets(t *testing.T) {
+	var requests int32
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/magnet/upload": {Respond: func(r *http.Request) string {
+			atomic.AddInt32(&requests, 1)
+			_ = r.ParseForm()
+			var magnets []string
+			for _, magnet := range r.PostForm["magnets[]"] {
+				if magnet == "foo" {
+					magnets = append(magnets, `{"magnet":"foo","error":{"code":"MAGNET_INVALID_URI","message":"Magnet URI is invalid"}}`)
+				} else {
+					magnets = append(magnets, fmt.Sprintf(`{"magnet":%q,"hash":%q,"name":"noname","id":%v,"ready":true}`, magnet, magnet, len(magnet)))
+				}
+			}
+			return `{"status":"success","data":{"magnets":[` + strings.Join(magnets, ",") + `]}}`
+		}},
+	})
+
+	// More magnets than fit into one request. They aren't valid info hashes, so their hashes are returned as they are.
+	var hashes []string
+	for i := 0; i < alldebrid.MaxMagnetsPerUpload+1; i++ {
+		hashes = append(hashes, strings.Repeat("-", i+1))
+	}
+	hashes = append(hashes, "foo")
+
+	uploads, err := client.UploadMagnets(context.Background(), hashes...)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	require.Len(t, uploads, len(hashes))
+	for i, upload := range uploads[:len(uploads)-1] {
+		require.Nil(t, upload.Error)
+		require.Equal(t, hashes[i], upload.Hash)
+		require.Equal(t, i+1, upload.ID)
+		require.True(t, upload.Ready)
+	}
+	require.Equal(t, "foo", uploads[len(uploads)-1].Magnet.Magnet)
+	require.Equal(t, "MAGNET_INVALID_URI", uploads[len(uploads)-1].Error.Code)
+
+	// Hashes are normalized
+	hash := "50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139"
+	uploads, err = client.UploadMagnets(context.Background(), strings.ToLower(hash))
+	require.NoError(t, err)
+	require.Equal(t, hash, uploads[0].Hash)
+
+	// Results that can't be matched with the magnets
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/magnet/upload": {Body: `{"status":"success","data":{"magnets":[{"magnet":"AAA","hash":"AAA","name":"noname","id":1,"ready":true}]}}`},
+	})
+	_, err = client.UploadMagnets(context.Background(), "AAA", "AAA")
+	require.Error(t, err)
+}
+
+func TestLiveStatusTracker(t *testing.T) {
+	var sessions []string
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/magnet/status": {Respond: func(r *http.Request) string {
+			session := r.URL.Query().Get("session")
+			if len(sessions) == 0 || sessions[len(sessions)-1] != session {
+				sessions = append(sessions, session)
+			}
+			switch r.URL.Query().Get("counter") {
+			case "0":
+				return `{"status":"success","data":{"magnets":[` +
+					`{"id":1,"filename":"a","status":"Downloading","statusCode":1,"downloaded":10,"uploadDate":1604756220,"completionDate":0,"links":[]},` +
+					`{"id":2,"filename":"b","status":"Ready","statusCode":4,"downloaded":20,"links":[{"link":"https://uptobox.com/b","filename":"b.mkv","size":20}]}` +
+					`],"counter":1,"fullsync":true}}`
+			case "1":
+				return `{"status":"success","data":{"magnets":[` +
+					`{"id":1,"status":"Ready","statusCode":4,"downloaded":100,"completionDate":"1604756280"},` +
+					`{"id":2,"deleted":true},` +
+					`{"id":3,"filename":"c","status":"In Queue","statusCode":0}` +
+					`],"counter":2}}`
+			default:
+				// Delta for a magnet the tracker doesn't know
+				return `{"status":"success","data":{"magnets":[{"id":9,"downloaded":5}],"counter":3}}`
+			}
+		}},
+	})
+	tracker := alldebrid.NewLiveStatusTracker(client)
+	ctx := context.Background()
+
+	statuses, err := tracker.Update(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, 2, statuses[1].ID)
+	require.Equal(t, "/b.mkv", statuses[1].Links[0].Path())
+
+	statuses, err = tracker.Update(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	// Changed fields are applied, others are kept
+	require.Equal(t, 1, statuses[0].ID)
+	require.Equal(t, "a", statuses[0].Filename)
+	require.Equal(t, alldebrid.StatusCode_Ready, statuses[0].StatusCode)
+	require.Equal(t, int64(100), statuses[0].Downloaded)
+	require.True(t, statuses[0].UploadDate.Equal(time.Unix(1604756220, 0)))
+	require.True(t, statuses[0].CompletionDate.Equal(time.Unix(1604756280, 0)))
+	require.Equal(t, 3, statuses[1].ID)
+	_, found := tracker.Get(2)
+	require.False(t, found)
+
+	// Desync leads to a full refresh with a new session
+	statuses, err = tracker.Update(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, []int{1, 2}, []int{statuses[0].ID, statuses[1].ID})
+	require.Equal(t, "Downloading", statuses[0].Status)
+	require.Len(t, sessions, 2)
+	require.Equal(t, statuses, tracker.Magnets())
+}
+
+func TestUploadTorrent(t *testing.T) {
+	torrent := []byte("d4:infod4:name5:Moviee")
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/magnet/upload/file": {
+			Body: `{"status":"success","data":{"files":[{"file":"movie.torrent","name":"Movie","id":42,"hash":"50b7dafb7137cbecf045f78e8efbe4ac1a90d139","size":1234,"ready":true}]}}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				file, header, err := r.FormFile("files[]")
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer file.Close()
+				assert.Equal(t, "movie.torrent", header.Filename)
+				content, err := ioutil.ReadAll(file)
+				assert.NoError(t, err)
+				assert.Equal(t, torrent, content)
+			},
+		},
+	})
+	ctx := context.Background()
+
+	magnet, err := client.UploadTorrent(ctx, "movie.torrent", torrent)
+	require.NoError(t, err)
+	require.Equal(t, alldebrid.Magnet{Name: "Movie", ID: 42, Hash: "50b7dafb7137cbecf045f78e8efbe4ac1a90d139", Size: 1234, Ready: true}, magnet)
+
+	// Errors for single files
+	client = newFakeClient(t, map[string]fakeResponse{
+		"/magnet/upload/file": {Body: `{"status":"success","data":{"files":[{"file":"movie.torrent","error":{"code":"MAGNET_FILE_UPLOAD_FAILED","message":"File upload failed"}}]}}`},
+	})
+	_, err = client.UploadTorrent(ctx, "movie.torrent", torrent)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "File upload failed")
+}
@@ -0,0 +1,117 @@
+package alldebrid
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
+
+// ParseFiles decodes the files of a link (Link.Files) into a typed tree.
+// The format depends on the version of the status (Status.Version):
+//   - Version 1: Files are names and folders are objects that map the folder name to its content, like [{"Folder": ["file.mkv"]}]
+//   - Version 2: Files and folders are objects with the name "n", size "s" and content "e", like [{"n": "Folder", "e": [{"n": "file.mkv", "s": 123}]}]
+//
+// If the version is unknown (0), the format is detected per element.
+func ParseFiles(files []interface{}, version int) ([]FileNode, error) {
+	if files == nil {
+		return nil, nil
+	}
+	nodes := make([]FileNode, 0, len(files))
+	for _, file := range files {
+		switch v := file.(type) {
+		case string:
+			nodes = append(nodes, FileNode{Name: v})
+		case map[string]interface{}:
+			if _, hasName := v["n"]; version >= 2 || (version == 0 && hasName) {
+				node, err := parseFileV2(v)
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, node)
+				continue
+			}
+			// Version 1 folders. There's usually one per object, but multiple are possible.
+			names := make([]string, 0, len(v))
+			for name := range v {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				content := v[name]
+				children, ok := content.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("unexpected content of folder %v: %T", name, content)
+				}
+				childNodes, err := ParseFiles(children, version)
+				if err != nil {
+					return nil, err
+				}
+				if childNodes == nil {
+					childNodes = []FileNode{}
+				}
+				nodes = append(nodes, FileNode{Name: name, Children: childNodes})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected file element: %T", file)
+		}
+	}
+	return nodes, nil
+}
+
+func parseFileV2(file map[string]interface{}) (FileNode, error) {
+	name, ok := file["n"].(string)
+	if !ok {
+		return FileNode{}, fmt.Errorf("unexpected file name: %T", file["n"])
+	}
+	node := FileNode{Name: name}
+	// JSON numbers are decoded as float64
+	if size, ok := file["s"].(float64); ok {
+		node.Size = int64(size)
+	}
+	if entries, found := file["e"]; found {
+		children, ok := entries.([]interface{})
+		if !ok {
+			return FileNode{}, fmt.Errorf("unexpected content of folder %v: %T", name, entries)
+		}
+		childNodes, err := ParseFiles(children, 2)
+		if err != nil {
+			return FileNode{}, err
+		}
+		if childNodes == nil {
+			childNodes = []FileNode{}
+		}
+		node.Children = childNodes
+	}
+	return node, nil
+}
+
+// FlattenFiles returns the files of the tree with their full path, without the folders.
+// Like with debrid.Torrent, paths start with "/".
+func FlattenFiles(tree []FileNode) []debrid.File {
+	var files []debrid.File
+	flattenFiles(tree, "/", &files)
+	return files
+}
+
+func flattenFiles(tree []FileNode, dir string, files *[]debrid.File) {
+	for _, node := range tree {
+		p := path.Join(dir, node.Name)
+		if node.IsDir() {
+			flattenFiles(node.Children, p, files)
+		} else {
+			*files = append(*files, debrid.File{Path: p, Size: node.Size})
+		}
+	}
+}
+
+// Path returns the full path of the link's file in the torrent, based on its file tree.
+// If the tree doesn't contain exactly one file, it falls back to the file name.
+func (l Link) Path() string {
+	files := FlattenFiles(l.Tree)
+	if len(files) != 1 {
+		return "/" + l.Filename
+	}
+	return files[0].Path
+}
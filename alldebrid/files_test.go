@@ -0,0 +1,67 @@
+package alldebrid_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+	"github.com/deflix-tv/go-debrid/alldebrid"
+)
+
+func TestStatusFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+	}{
+		{"version 1", `{"id":1,"version":1,"links":[` +
+			`{"link":"https://uptobox.com/abc","filename":"Movie.mkv","size":3000,"files":[{"Movie.2020.1080p":["Movie.mkv"]}]},` +
+			`{"link":"https://uptobox.com/def","filename":"Movie.mkv","size":4000,"files":[{"Movie.2020.1080p":[{"Sample":["Movie.mkv"]}]}]}]}`},
+		{"version 2", `{"id":1,"version":2,"links":[` +
+			`{"link":"https://uptobox.com/abc","filename":"Movie.mkv","size":3000,"files":[{"n":"Movie.2020.1080p","e":[{"n":"Movie.mkv","s":3000}]}]},` +
+			`{"link":"https://uptobox.com/def","filename":"Movie.mkv","size":4000,"files":[{"n":"Movie.2020.1080p","e":[{"n":"Sample","e":[{"n":"Movie.mkv","s":4000}]}]}]}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := alldebrid.Status{}
+			err := json.Unmarshal([]byte(test.status), &status)
+			require.NoError(t, err)
+			require.Len(t, status.Links, 2)
+
+			tree := status.Links[1].Tree
+			require.Len(t, tree, 1)
+			require.True(t, tree[0].IsDir())
+			require.Equal(t, "Movie.2020.1080p", tree[0].Name)
+			require.Equal(t, "Sample", tree[0].Children[0].Name)
+			require.False(t, tree[0].Children[0].Children[0].IsDir())
+
+			require.Equal(t, "/Movie.2020.1080p/Movie.mkv", status.Links[0].Path())
+			require.Equal(t, "/Movie.2020.1080p/Sample/Movie.mkv", status.Links[1].Path())
+
+			// The full paths allow excluding samples that are larger than the main file
+			link, err := alldebrid.SelectFile(status, debrid.DefaultFileSelector)
+			require.NoError(t, err)
+			require.Equal(t, "https://uptobox.com/abc", link.Link)
+		})
+	}
+}
+
+func TestFlattenFiles(t *testing.T) {
+	tree := []alldebrid.FileNode{
+		{Name: "Show.S01", Children: []alldebrid.FileNode{
+			{Name: "Show.S01E01.mkv", Size: 100},
+			{Name: "Show.S01E02.mkv", Size: 200},
+			{Name: "Extras", Children: []alldebrid.FileNode{}},
+		}},
+		{Name: "Show.nfo", Size: 1},
+	}
+	require.Equal(t, []debrid.File{
+		{Path: "/Show.S01/Show.S01E01.mkv", Size: 100},
+		{Path: "/Show.S01/Show.S01E02.mkv", Size: 200},
+		{Path: "/Show.nfo", Size: 1},
+	}, alldebrid.FlattenFiles(tree))
+
+	// Fallback to the file name without a tree
+	require.Equal(t, "/Movie.mkv", alldebrid.Link{Filename: "Movie.mkv"}.Path())
+}
@@ -0,0 +1,78 @@
+package alldebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// GetHosts fetches and returns the hosters, streaming sites and redirectors that are supported by AllDebrid.
+// The returned quotas are user-specific.
+func (c *Client) GetHosts(ctx context.Context) (Hosts, error) {
+	c.logger.Debug("Getting hosts...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts")
+	if err != nil {
+		return Hosts{}, fmt.Errorf("couldn't get hosts: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return Hosts{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	hostsJSON := gjson.GetBytes(resBytes, "data").Raw
+	hosts := Hosts{}
+	if err = json.Unmarshal([]byte(hostsJSON), &hosts); err != nil {
+		return Hosts{}, fmt.Errorf("couldn't unmarshal hosts: %w", err)
+	}
+
+	c.logger.Debug("Got hosts", zap.Int("hostCount", len(hosts.Hosts)), zapDebridService)
+	return hosts, nil
+}
+
+// GetHostDomains fetches and returns the domains of the hosters, streaming sites and redirectors that are supported by AllDebrid.
+func (c *Client) GetHostDomains(ctx context.Context) (HostDomains, error) {
+	c.logger.Debug("Getting host domains...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts/domains")
+	if err != nil {
+		return HostDomains{}, fmt.Errorf("couldn't get host domains: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return HostDomains{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	domainsJSON := gjson.GetBytes(resBytes, "data").Raw
+	domains := HostDomains{}
+	if err = json.Unmarshal([]byte(domainsJSON), &domains); err != nil {
+		return HostDomains{}, fmt.Errorf("couldn't unmarshal host domains: %w", err)
+	}
+
+	c.logger.Debug("Got host domains", zap.Int("hostCount", len(domains.Hosts)), zapDebridService)
+	return domains, nil
+}
+
+// GetHostPriority fetches and returns the priority of the hosters, mapped by their name.
+// When a file is available on multiple hosters, the one with the lowest priority value should be used.
+func (c *Client) GetHostPriority(ctx context.Context) (map[string]int, error) {
+	c.logger.Debug("Getting host priority...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts/priority")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get host priority: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return nil, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	priorityJSON := gjson.GetBytes(resBytes, "data.hosts").Raw
+	priority := map[string]int{}
+	if err = json.Unmarshal([]byte(priorityJSON), &priority); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal host priority: %w", err)
+	}
+
+	c.logger.Debug("Got host priority", zap.String("priority", fmt.Sprintf("%+v", priority)), zapDebridService)
+	return priority, nil
+}
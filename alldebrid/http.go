@@ -1,21 +1,33 @@
 package alldebrid
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/tidwall/gjson"
 	"go.uber.org/zap"
 )
 
+// authParams returns the query parameters that AllDebrid requires for all requests.
+// The API key is omitted for a client without one, which is only useful for the PIN flow.
+func (c *Client) authParams() string {
+	if c.apiKey == "" {
+		return "agent=go-debrid"
+	}
+	return "agent=go-debrid&apikey=" + c.apiKey
+}
+
 func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
 	if strings.Contains(url, "?") {
-		url += "&agent=go-debrid&apikey=" + c.apiKey
+		url += "&" + c.authParams()
 	} else {
-		url += "?agent=go-debrid&apikey=" + c.apiKey
+		url += "?" + c.authParams()
 	}
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -51,8 +63,21 @@ func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
 	return resBody, nil
 }
 
+// getSuccess sends a GET request to an endpoint that only responds with a status and a message, and checks the status.
+func (c *Client) getSuccess(ctx context.Context, url string) error {
+	resBytes, err := c.get(ctx, url)
+	if err != nil {
+		return err
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	return nil
+}
+
 func (c *Client) post(ctx context.Context, url string, data url.Values) ([]byte, error) {
-	url += "?agent=go-debrid&apikey=" + c.apiKey
+	url += "?" + c.authParams()
 	req, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create POST request: %w", err)
@@ -86,3 +111,53 @@ func (c *Client) post(ctx context.Context, url string, data url.Values) ([]byte,
 	}
 	return resBody, nil
 }
+
+// postFile sends a multipart/form-data POST request with a single file.
+func (c *Client) postFile(ctx context.Context, url, fieldName, fileName string, content []byte) ([]byte, error) {
+	url += "?" + c.authParams()
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	fw, err := mw.CreateFormFile(fieldName, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create multipart form file: %w", err)
+	}
+	if _, err = fw.Write(content); err != nil {
+		return nil, fmt.Errorf("couldn't write multipart form file: %w", err)
+	}
+	if err = mw.Close(); err != nil {
+		return nil, fmt.Errorf("couldn't close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create POST request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	for headerKey, headerVal := range c.opts.ExtraHeaders {
+		req.Header.Add(headerKey, headerVal)
+	}
+
+	c.logger.Debug("Sending request", zap.String("request", fmt.Sprintf("%+v", req)), zapDebridService)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't send POST request: %w", err)
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	c.logger.Debug("Got response", zap.Int("status", res.StatusCode), zap.NamedError("bodyReadError", err), zap.ByteString("response", resBody), zapDebridService)
+
+	// Check server response.
+	if res.StatusCode != http.StatusOK {
+		if err, found := errMap[res.StatusCode]; found {
+			return resBody, err
+		}
+		// resBody can be nil if above ioutil.ReadAll failed, but in that case we don't care about the related error.
+		return resBody, fmt.Errorf("bad HTTP response status: %v", res.Status)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read response body: %w", err)
+	}
+	return resBody, nil
+}
@@ -23,6 +23,8 @@ type LegacyClientOptions struct {
 	Timeout      time.Duration
 	CacheAge     time.Duration
 	ExtraHeaders []string
+	// Selects the file to stream from a torrent with multiple files. Defaults to debrid.DefaultFileSelector.
+	FileSelector debrid.FileSelector
 }
 
 var DefaultLegacyClientOpts = LegacyClientOptions{
@@ -40,6 +42,7 @@ type LegacyClient struct {
 	availabilityCache debrid.Cache
 	cacheAge          time.Duration
 	extraHeaders      map[string]string
+	fileSelector      debrid.FileSelector
 	logger            *zap.Logger
 }
 
@@ -65,6 +68,10 @@ func NewLegacyClient(opts LegacyClientOptions, apiKeyCache, availabilityCache de
 		}
 	}
 
+	if opts.FileSelector == nil {
+		opts.FileSelector = debrid.DefaultFileSelector
+	}
+
 	return &LegacyClient{
 		baseURL: opts.BaseURL,
 		httpClient: &http.Client{
@@ -74,6 +81,7 @@ func NewLegacyClient(opts LegacyClientOptions, apiKeyCache, availabilityCache de
 		availabilityCache: availabilityCache,
 		cacheAge:          opts.CacheAge,
 		extraHeaders:      extraHeaderMap,
+		fileSelector:      opts.FileSelector,
 		logger:            logger,
 	}, nil
 }
@@ -136,6 +144,12 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, apiKey stri
 	var unknownAvailailabilityValues []string
 	for _, infoHash := range infoHashes {
 		zapFieldInfoHash := zap.String("infoHash", infoHash)
+		// Normalize so that cache keys and results are consistent, no matter how the info_hash was passed
+		infoHash, err := debrid.NormalizeInfoHash(infoHash)
+		if err != nil {
+			c.logger.Error("Couldn't normalize info_hash", zap.Error(err), zapFieldInfoHash, zapFieldDebridSite, zapFieldAPItoken)
+			continue
+		}
 		created, found, err := c.availabilityCache.Get(infoHash)
 		if err != nil {
 			c.logger.Error("Couldn't decode availability cache item", zap.Error(err), zapFieldInfoHash, zapFieldDebridSite, zapFieldAPItoken)
@@ -199,8 +213,11 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, apiKey stri
 			if !instant {
 				continue
 			}
-			infoHash := magnet.Get("hash").String()
-			infoHash = strings.ToUpper(infoHash)
+			infoHash, err := debrid.NormalizeInfoHash(magnet.Get("hash").String())
+			if err != nil {
+				c.logger.Error("Couldn't normalize info_hash", zap.Error(err), zap.String("infoHash", magnet.Get("hash").String()), zapFieldDebridSite, zapFieldAPItoken)
+				continue
+			}
 			result = append(result, infoHash)
 			// Create cache item
 			if err = c.availabilityCache.Set(infoHash); err != nil {
@@ -212,6 +229,12 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, apiKey stri
 }
 
 func (c *LegacyClient) GetStreamURL(ctx context.Context, magnetURL, apiKey string) (string, error) {
+	return c.GetStreamURLWithSelector(ctx, magnetURL, apiKey, c.fileSelector)
+}
+
+// GetStreamURLWithSelector is like GetStreamURL, but uses the given file selector instead of the one from the client options.
+// This is useful for selecting a specific episode from a season pack, see debrid.EpisodeSelector.
+func (c *LegacyClient) GetStreamURLWithSelector(ctx context.Context, magnetURL, apiKey string, selector debrid.FileSelector) (string, error) {
 	zapFieldDebridSite := zap.String("debridSite", "AllDebrid")
 	zapFieldAPIkey := zap.String("apiKey", apiKey)
 	c.logger.Debug("Adding magnet to AllDebrid...", zapFieldDebridSite, zapFieldAPIkey)
@@ -249,7 +272,8 @@ func (c *LegacyClient) GetStreamURL(ctx context.Context, magnetURL, apiKey strin
 		return "", fmt.Errorf("Got error response from api.alldebrid.com: %v", errMsg)
 	}
 	linkResults := gjson.GetBytes(resBytes, "data.magnets.links").Array()
-	link, err := selectLink(ctx, linkResults)
+	filesVersion := int(gjson.GetBytes(resBytes, "data.magnets.version").Int())
+	link, err := selectLink(ctx, linkResults, filesVersion, selector)
 	if err != nil {
 		return "", fmt.Errorf("Couldn't find proper link in magnet status: %v", err)
 	} else if link == "" {
@@ -344,21 +368,27 @@ func (c *LegacyClient) post(ctx context.Context, url, apiKey string, data url.Va
 	return ioutil.ReadAll(res.Body)
 }
 
-func selectLink(ctx context.Context, linkResults []gjson.Result) (string, error) {
+func selectLink(ctx context.Context, linkResults []gjson.Result, filesVersion int, selector debrid.FileSelector) (string, error) {
 	// Precondition check
 	if len(linkResults) == 0 {
 		return "", fmt.Errorf("Empty slice of links")
 	}
 
-	var link string
-	var size int64
-	for _, res := range linkResults {
-		if res.Get("size").Int() > size {
-			size = res.Get("size").Int()
-			link = res.Get("link").String()
+	files := make([]debrid.File, len(linkResults))
+	for i, res := range linkResults {
+		link := Link{Filename: res.Get("filename").String()}
+		// Use the full path if possible, falls back to the file name otherwise
+		if filesValue, ok := res.Get("files").Value().([]interface{}); ok {
+			link.Tree, _ = ParseFiles(filesValue, filesVersion)
 		}
+		files[i] = debrid.File{Path: link.Path(), Size: res.Get("size").Int()}
+	}
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return "", err
 	}
 
+	link := linkResults[i].Get("link").String()
 	if link == "" {
 		return "", fmt.Errorf("No link found")
 	}
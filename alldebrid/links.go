@@ -0,0 +1,171 @@
+package alldebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultDelayedLinkInterval is the interval in which UnlockAndWait first checks the status of a delayed link by default.
+	DefaultDelayedLinkInterval = time.Second
+	// MaxDelayedLinkInterval is the maximum interval in which UnlockAndWait checks the status of a delayed link.
+	MaxDelayedLinkInterval = 10 * time.Second
+)
+
+// GetLinkInfos fetches and returns info about links without unlocking them.
+// The password can be empty if the links aren't password protected.
+// Links that can't be processed are part of the returned list, with their Error being set.
+func (c *Client) GetLinkInfos(ctx context.Context, password string, links ...string) ([]LinkInfo, error) {
+	c.logger.Debug("Getting link infos...", zapDebridService)
+
+	data := url.Values{"link[]": links}
+	if password != "" {
+		data.Set("password", password)
+	}
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/link/infos?"+data.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get link infos: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return nil, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	infosJSON := gjson.GetBytes(resBytes, "data.infos").Raw
+	infos := []LinkInfo{}
+	if err = json.Unmarshal([]byte(infosJSON), &infos); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal link infos: %w", err)
+	}
+
+	c.logger.Debug("Got link infos", zap.String("infos", fmt.Sprintf("%+v", infos)), zapDebridService)
+	return infos, nil
+}
+
+// GetRedirectorLinks extracts the links from a redirector or link protector link.
+// The returned links can then be unlocked with Unlock.
+func (c *Client) GetRedirectorLinks(ctx context.Context, link string) ([]string, error) {
+	c.logger.Debug("Getting redirector links...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/link/redirector?link="+url.QueryEscape(link))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get redirector links: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return nil, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	linksJSON := gjson.GetBytes(resBytes, "data.links").Raw
+	links := []string{}
+	if err = json.Unmarshal([]byte(linksJSON), &links); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal redirector links: %w", err)
+	}
+
+	c.logger.Debug("Got redirector links", zap.Strings("links", links), zapDebridService)
+	return links, nil
+}
+
+// GetDelayedLink fetches and returns the status of a link that needs time to generate.
+// The ID must be the Download.Delayed from unlocking a link.
+func (c *Client) GetDelayedLink(ctx context.Context, id int) (DelayedLink, error) {
+	c.logger.Debug("Getting delayed link...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/link/delayed?id="+strconv.Itoa(id))
+	if err != nil {
+		return DelayedLink{}, fmt.Errorf("couldn't get delayed link: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return DelayedLink{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	delayedJSON := gjson.GetBytes(resBytes, "data").Raw
+	delayed := DelayedLink{}
+	if err = json.Unmarshal([]byte(delayedJSON), &delayed); err != nil {
+		return DelayedLink{}, fmt.Errorf("couldn't unmarshal delayed link: %w", err)
+	}
+
+	c.logger.Debug("Got delayed link", zap.String("delayedLink", fmt.Sprintf("%+v", delayed)), zapDebridService)
+	return delayed, nil
+}
+
+// UnlockAndWait unlocks a link like Unlock, but if the link needs time to generate, it waits until the link is ready.
+// It polls the delayed link status, starting with the given interval and doubling it after each poll up to MaxDelayedLinkInterval.
+// An interval of 0 leads to DefaultDelayedLinkInterval being used.
+// It returns ErrorDelayedLinkFailed if AllDebrid couldn't generate the link, or the context's error when the context is done.
+// The returned Download's Link is the generated link.
+func (c *Client) UnlockAndWait(ctx context.Context, link string, interval time.Duration) (Download, error) {
+	dl, err := c.Unlock(ctx, link)
+	if err != nil {
+		return Download{}, err
+	}
+	return c.waitForDelayedLink(ctx, dl, interval)
+}
+
+// waitForDelayedLink polls the status of a delayed link until it's ready, and returns the download with the generated link.
+// Downloads that aren't delayed are returned as they are.
+func (c *Client) waitForDelayedLink(ctx context.Context, dl Download, interval time.Duration) (Download, error) {
+	if dl.Delayed == 0 {
+		return dl, nil
+	}
+
+	if interval == 0 {
+		interval = DefaultDelayedLinkInterval
+	}
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Download{}, ctx.Err()
+		case <-timer.C:
+		}
+
+		delayed, err := c.GetDelayedLink(ctx, dl.Delayed)
+		if err != nil {
+			return Download{}, err
+		}
+		switch delayed.Status {
+		case DelayedStatus_Ready:
+			dl.Link = delayed.Link
+			return dl, nil
+		case DelayedStatus_Error:
+			return Download{}, ErrorDelayedLinkFailed
+		}
+
+		if interval *= 2; interval > MaxDelayedLinkInterval {
+			interval = MaxDelayedLinkInterval
+		}
+	}
+}
+
+// GetStreamingLink returns the direct link of an alternative stream of an unlocked link.
+// The ID must be the Download.ID from unlocking the link, and the stream ID one of the Download.Streams' IDs, see SelectStream.
+// If the link needs time to generate, it waits until the link is ready, like UnlockAndWait with the given interval.
+func (c *Client) GetStreamingLink(ctx context.Context, id, streamID string, interval time.Duration) (Download, error) {
+	c.logger.Debug("Getting streaming link...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/link/streaming?id="+url.QueryEscape(id)+"&stream="+url.QueryEscape(streamID))
+	if err != nil {
+		return Download{}, fmt.Errorf("couldn't get streaming link: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return Download{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	downloadJSON := gjson.GetBytes(resBytes, "data").Raw
+	dl := Download{}
+	if err = json.Unmarshal([]byte(downloadJSON), &dl); err != nil {
+		return Download{}, fmt.Errorf("couldn't unmarshal streaming link: %w", err)
+	}
+	if dl, err = c.waitForDelayedLink(ctx, dl, interval); err != nil {
+		return Download{}, fmt.Errorf("couldn't wait for delayed streaming link: %w", err)
+	}
+
+	c.logger.Debug("Got streaming link", zap.String("download", fmt.Sprintf("%+v", dl)), zapDebridService)
+	return dl, nil
+}
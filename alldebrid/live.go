@@ -0,0 +1,154 @@
+package alldebrid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// errDesync is returned internally when a delta can't be applied to the local state.
+var errDesync = errors.New("live status out of sync")
+
+// LiveStatusTracker keeps track of the status of all of a user's magnets using AllDebrid's live status mode.
+// Instead of the whole list, AllDebrid then only sends the magnets and fields that changed since the previous request,
+// which the tracker applies to its local state.
+// If the deltas can't be applied, for example because a changed magnet isn't known locally, the tracker does a full refresh.
+// It's safe for concurrent use.
+type LiveStatusTracker struct {
+	client *Client
+
+	lock sync.Mutex
+	// Source of the session IDs. Seeded per tracker, so that trackers in different processes don't share sessions.
+	rand    *rand.Rand
+	session int
+	counter int
+	magnets map[int]Status
+}
+
+// NewLiveStatusTracker returns a new LiveStatusTracker that uses the client for its requests.
+// The first Update fetches the status of all magnets.
+func NewLiveStatusTracker(client *Client) *LiveStatusTracker {
+	t := &LiveStatusTracker{
+		client:  client,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		magnets: map[int]Status{},
+	}
+	t.session = t.rand.Intn(1 << 30)
+	return t
+}
+
+// Update fetches the changes since the previous update, applies them and returns the status of all magnets, sorted by ID.
+func (t *LiveStatusTracker) Update(ctx context.Context) ([]Status, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	err := t.update(ctx)
+	if errors.Is(err, errDesync) {
+		t.client.logger.Debug("Live status out of sync, doing a full refresh", zapDebridService)
+		t.reset()
+		err = t.update(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.list(), nil
+}
+
+// Magnets returns the status of all magnets as of the last update, sorted by ID.
+func (t *LiveStatusTracker) Magnets() []Status {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.list()
+}
+
+// Get returns the status of a magnet as of the last update.
+func (t *LiveStatusTracker) Get(id int) (Status, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status, found := t.magnets[id]
+	return status, found
+}
+
+// Must only be called while holding the lock.
+func (t *LiveStatusTracker) update(ctx context.Context) error {
+	c := t.client
+	c.logger.Debug("Getting live status...", zap.Int("counter", t.counter), zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/magnet/status?session="+strconv.Itoa(t.session)+"&counter="+strconv.Itoa(t.counter))
+	if err != nil {
+		return fmt.Errorf("couldn't get live status: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+
+	magnets := t.magnets
+	if gjson.GetBytes(resBytes, "data.fullsync").Bool() || t.counter == 0 {
+		magnets = map[int]Status{}
+	}
+	// Work on a copy, so that the local state stays consistent if a delta can't be applied
+	updated := make(map[int]Status, len(magnets))
+	for id, status := range magnets {
+		updated[id] = status
+	}
+	for _, delta := range gjson.GetBytes(resBytes, "data.magnets").Array() {
+		id := int(delta.Get("id").Int())
+		if delta.Get("deleted").Bool() {
+			delete(updated, id)
+			continue
+		}
+		status, found := updated[id]
+		// New magnets must contain all fields
+		if !found && !delta.Get("status").Exists() {
+			return fmt.Errorf("%w: unknown magnet %v", errDesync, id)
+		}
+		// Don't decode into the existing links' backing array, which is shared with previously returned statuses
+		if delta.Get("links").Exists() {
+			status.Links = nil
+		}
+		// Unmarshalling into the existing status only overwrites the fields that are part of the delta
+		if err = json.Unmarshal([]byte(delta.Raw), &status); err != nil {
+			return fmt.Errorf("couldn't unmarshal live status: %w", err)
+		}
+		if delta.Get("links").Exists() {
+			c.logUnparsedFiles(status)
+		}
+		updated[id] = status
+	}
+
+	t.magnets = updated
+	t.counter = int(gjson.GetBytes(resBytes, "data.counter").Int())
+	c.logger.Debug("Got live status", zap.Int("magnetCount", len(updated)), zap.Int("counter", t.counter), zapDebridService)
+	return nil
+}
+
+// Must only be called while holding the lock.
+func (t *LiveStatusTracker) reset() {
+	t.session = t.rand.Intn(1 << 30)
+	t.counter = 0
+	t.magnets = map[int]Status{}
+}
+
+// Must only be called while holding the lock.
+func (t *LiveStatusTracker) list() []Status {
+	statuses := make([]Status, 0, len(t.magnets))
+	for _, status := range t.magnets {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ID < statuses[j].ID
+	})
+	return statuses
+}
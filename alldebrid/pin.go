@@ -0,0 +1,94 @@
+package alldebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// DefaultPinCheckInterval is the interval in which WaitForAPIKey checks the PIN status by default.
+const DefaultPinCheckInterval = 5 * time.Second
+
+// GetPin starts AllDebrid's PIN authentication flow and returns the PIN that the user has to enter on Pin.UserURL.
+// The client doesn't require an API key for this, so it can be created with an empty one.
+func (c *Client) GetPin(ctx context.Context) (Pin, error) {
+	c.logger.Debug("Getting PIN...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/pin/get")
+	if err != nil {
+		return Pin{}, fmt.Errorf("couldn't get PIN: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return Pin{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	pinJSON := gjson.GetBytes(resBytes, "data").Raw
+	pin := Pin{}
+	if err = json.Unmarshal([]byte(pinJSON), &pin); err != nil {
+		return Pin{}, fmt.Errorf("couldn't unmarshal PIN: %w", err)
+	}
+	pin.Expiry = time.Now().Add(time.Duration(pin.ExpiresIn) * time.Second)
+
+	c.logger.Debug("Got PIN", zap.String("pin", pin.Pin), zapDebridService)
+	return pin, nil
+}
+
+// CheckPin checks if the user entered the PIN, in which case the returned status contains the user's API key.
+// ErrorPinExpired is returned if the PIN expired.
+func (c *Client) CheckPin(ctx context.Context, pin Pin) (PinStatus, error) {
+	c.logger.Debug("Checking PIN...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/pin/check?check="+url.QueryEscape(pin.Check)+"&pin="+url.QueryEscape(pin.Pin))
+	if err != nil {
+		return PinStatus{}, fmt.Errorf("couldn't check PIN: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		if gjson.GetBytes(resBytes, "error.code").String() == "PIN_EXPIRED" {
+			return PinStatus{}, ErrorPinExpired
+		}
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return PinStatus{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	statusJSON := gjson.GetBytes(resBytes, "data").Raw
+	status := PinStatus{}
+	if err = json.Unmarshal([]byte(statusJSON), &status); err != nil {
+		return PinStatus{}, fmt.Errorf("couldn't unmarshal PIN status: %w", err)
+	}
+
+	c.logger.Debug("Checked PIN", zap.Bool("activated", status.Activated), zapDebridService)
+	return status, nil
+}
+
+// WaitForAPIKey checks the PIN status in the given interval until the user entered the PIN, and then returns the user's API key.
+// An interval of 0 leads to DefaultPinCheckInterval being used.
+// It returns ErrorPinExpired when the PIN expires, or the context's error when the context is done.
+func (c *Client) WaitForAPIKey(ctx context.Context, pin Pin, interval time.Duration) (string, error) {
+	if interval == 0 {
+		interval = DefaultPinCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := c.CheckPin(ctx, pin)
+		if err != nil {
+			return "", err
+		}
+		if status.Activated && status.APIKey != "" {
+			return status.APIKey, nil
+		}
+		if !pin.Expiry.IsZero() && time.Now().After(pin.Expiry) {
+			return "", ErrorPinExpired
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package alldebrid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/alldebrid"
+)
+
+func TestPinFlow(t *testing.T) {
+	var checks int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "go-debrid", r.URL.Query().Get("agent"))
+		_, hasAPIKey := r.URL.Query()["apikey"]
+		assert.False(t, hasAPIKey)
+		switch r.URL.Path {
+		case "/pin/get":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"pin":"ABCD","check":"CHECK","expires_in":600,"user_url":"https://alldebrid.com/pin/?pin=ABCD","base_url":"https://alldebrid.com/pin/"}}`))
+		case "/pin/check":
+			assert.Equal(t, "ABCD", r.URL.Query().Get("pin"))
+			assert.Equal(t, "CHECK", r.URL.Query().Get("check"))
+			// The user enters the PIN after the first check
+			if atomic.AddInt32(&checks, 1) == 1 {
+				_, _ = w.Write([]byte(`{"status":"success","data":{"activated":false,"expires_in":590}}`))
+			} else {
+				_, _ = w.Write([]byte(`{"status":"success","data":{"activated":true,"expires_in":580,"apikey":"KEY"}}`))
+			}
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	opts := alldebrid.DefaultClientOpts
+	opts.BaseURL = server.URL
+	client := alldebrid.NewClient(opts, "", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pin, err := client.GetPin(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ABCD", pin.Pin)
+	require.WithinDuration(t, time.Now().Add(600*time.Second), pin.Expiry, time.Minute)
+
+	apiKey, err := client.WaitForAPIKey(ctx, pin, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "KEY", apiKey)
+	require.Equal(t, int32(2), atomic.LoadInt32(&checks))
+}
+
+func TestPinExpiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("check") == "EXPIRED" {
+			_, _ = w.Write([]byte(`{"status":"error","error":{"code":"PIN_EXPIRED","message":"The pin has expired"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"activated":false,"expires_in":1}}`))
+	}))
+	defer server.Close()
+
+	opts := alldebrid.DefaultClientOpts
+	opts.BaseURL = server.URL
+	client := alldebrid.NewClient(opts, "", nil)
+	ctx := context.Background()
+
+	// Expiry reported by AllDebrid
+	_, err := client.WaitForAPIKey(ctx, alldebrid.Pin{Pin: "ABCD", Check: "EXPIRED"}, 10*time.Millisecond)
+	require.ErrorIs(t, err, alldebrid.ErrorPinExpired)
+
+	// Local expiry
+	pin := alldebrid.Pin{Pin: "ABCD", Check: "CHECK", Expiry: time.Now().Add(50 * time.Millisecond)}
+	_, err = client.WaitForAPIKey(ctx, pin, 10*time.Millisecond)
+	require.ErrorIs(t, err, alldebrid.ErrorPinExpired)
+
+	// Context cancellation
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	pin.Expiry = time.Now().Add(time.Hour)
+	_, err = client.WaitForAPIKey(ctx, pin, 10*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -1,7 +1,11 @@
 package alldebrid
 
 import (
+	"encoding/json"
 	"errors"
+	"time"
+
+	"github.com/deflix-tv/go-debrid/internal/jsonutil"
 )
 
 var (
@@ -19,6 +23,12 @@ var (
 	ErrorServerError = errors.New("server error")
 )
 
+// ErrorDelayedLinkFailed is returned when AllDebrid couldn't generate a delayed link.
+var ErrorDelayedLinkFailed = errors.New("delayed link generation failed")
+
+// ErrorPinExpired is returned when the user didn't enter the PIN of the PIN authentication flow before it expired.
+var ErrorPinExpired = errors.New("PIN expired")
+
 // TODO: Add error vars for the endpoint-specific errors, where the error codes are part of the HTTP response body, like "LINK_HOST_NOT_SUPPORTED" when trying to unlock a link.
 
 var errMap = map[int]error{
@@ -43,8 +53,8 @@ type User struct {
 	IsSubscribed bool `json:"isSubscribed"`
 	// true is account is in freedays trial, false if not
 	IsTrial bool `json:"isTrial"`
-	// 0 if user is not premium, or timestamp until user is premium
-	PremiumUntil int `json:"premiumUntil"`
+	// Zero if user is not premium, or point in time until user is premium
+	PremiumUntil time.Time `json:"premiumUntil"`
 	// Language used by the user on Alldebrid, eg. 'en', 'fr'. Default to fr
 	Lang string `json:"lang"`
 	// Preferer TLD used by the user, eg. 'fr', 'es'. Default to fr
@@ -57,6 +67,30 @@ type User struct {
 	RemainingTrialQuota int `json:"remainingTrialQuota,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the timestamp of the premium status.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	raw := struct {
+		*user
+		PremiumUntil jsonutil.UnixTime `json:"premiumUntil"`
+	}{user: (*user)(u)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	u.PremiumUntil = time.Time(raw.PremiumUntil)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return json.Marshal(struct {
+		user
+		PremiumUntil jsonutil.UnixTime `json:"premiumUntil"`
+	}{user: user(u), PremiumUntil: jsonutil.UnixTime(u.PremiumUntil)})
+}
+
 // Download represents an unlocked link.
 type Download struct {
 	// Requested link, simplified if it was not in canonical form
@@ -70,12 +104,12 @@ type Download struct {
 	// Unused
 	Paws bool `json:"paws,omitempty"`
 	// Filesize of the link's file
-	Filesize int `json:"filesize,omitempty"`
+	Filesize int64 `json:"filesize,omitempty"`
 	// Generation ID
 	ID string `json:"id,omitempty"`
 	// Matched host main domain
 	HostDomain string `json:"hostDomain,omitempty"`
-	// Delayed ID if link need time to generate
+	// Delayed ID if link need time to generate, see GetDelayedLink and UnlockAndWait
 	Delayed int `json:"delayed,omitempty"`
 }
 
@@ -86,7 +120,7 @@ type Stream struct {
 	// E.g. "mp4"
 	Ext string `json:"ext,omitempty"`
 	// File size in bytes
-	Filesize int    `json:"filesize,omitempty"`
+	Filesize int64  `json:"filesize,omitempty"`
 	Name     string `json:"name,omitempty"`
 	// Streamable direct link to the file
 	Link string `json:"link,omitempty"`
@@ -104,11 +138,18 @@ type Magnet struct {
 	// Magnet hash
 	Hash string `json:"hash,omitempty"`
 	// Magnet files size
-	Size int `json:"size,omitempty"`
+	Size int64 `json:"size,omitempty"`
 	// Whether the magnet is already available
 	Ready bool `json:"ready,omitempty"`
 }
 
+// MagnetUpload is the result of adding one of many magnets to AllDebrid.
+type MagnetUpload struct {
+	Magnet
+	// !! Only present if the magnet couldn't be added
+	Error *Error `json:"error,omitempty"`
+}
+
 // Status contains status info about a torrent that was previously uploaded to AllDebrid for a specific user.
 type Status struct {
 	// Magnet id
@@ -116,31 +157,69 @@ type Status struct {
 	// Magnet filename
 	Filename string `json:"filename,omitempty"`
 	// Magnet filesize
-	Size int `json:"size,omitempty"`
+	Size int64 `json:"size,omitempty"`
 	// Status in plain English
 	Status string `json:"status,omitempty"`
 	// Status code
 	StatusCode StatusCode `json:"statusCode,omitempty"`
-	// Downloaded data so far
-	Downloaded int `json:"downloaded,omitempty"`
-	// Uploaded data so far
-	Uploaded int `json:"uploaded,omitempty"`
+	// Downloaded data so far, in bytes
+	Downloaded int64 `json:"downloaded,omitempty"`
+	// Uploaded data so far, in bytes
+	Uploaded int64 `json:"uploaded,omitempty"`
 	// Seeders count
 	Seeders int `json:"seeders,omitempty"`
-	// Download speed
-	DownloadSpeed int `json:"downloadSpeed,omitempty"`
-	// Upload speed
-	UploadSpeed int `json:"uploadSpeed,omitempty"`
-	// Timestamp of the date of the magnet upload
-	UploadDate int `json:"uploadDate,omitempty"`
-	// Timestamp of the date of the magnet completion
-	CompletionDate int `json:"completionDate,omitempty"`
+	// Download speed in bytes per second
+	DownloadSpeed int64 `json:"downloadSpeed,omitempty"`
+	// Upload speed in bytes per second
+	UploadSpeed int64 `json:"uploadSpeed,omitempty"`
+	// Date of the magnet upload
+	UploadDate time.Time `json:"uploadDate,omitempty"`
+	// Date of the magnet completion. Zero if not completed yet.
+	CompletionDate time.Time `json:"completionDate,omitempty"`
 	// an array of link objects
 	Links []Link `json:"links,omitempty"`
 	// files array format
 	Version int `json:"version,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the timestamps, and the files of the links into typed trees according to the version.
+// The tree of a link whose files can't be parsed is nil.
+// Fields that aren't part of the data are left unchanged, so that a live status delta can be applied to an existing status.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	type status Status
+	raw := struct {
+		*status
+		UploadDate     *jsonutil.UnixTime `json:"uploadDate,omitempty"`
+		CompletionDate *jsonutil.UnixTime `json:"completionDate,omitempty"`
+	}{status: (*status)(s)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.UploadDate != nil {
+		s.UploadDate = time.Time(*raw.UploadDate)
+	}
+	if raw.CompletionDate != nil {
+		s.CompletionDate = time.Time(*raw.CompletionDate)
+	}
+	// A link with files in an unexpected format shouldn't make the whole status unusable, so its tree is left nil.
+	// The client logs these links, see logUnparsedFiles.
+	for i, link := range s.Links {
+		s.Links[i].Tree, _ = ParseFiles(link.Files, s.Version)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Status) MarshalJSON() ([]byte, error) {
+	type status Status
+	return json.Marshal(struct {
+		status
+		UploadDate     jsonutil.UnixTime `json:"uploadDate"`
+		CompletionDate jsonutil.UnixTime `json:"completionDate"`
+	}{status: status(s), UploadDate: jsonutil.UnixTime(s.UploadDate), CompletionDate: jsonutil.UnixTime(s.CompletionDate)})
+}
+
 // StatusCode indicates in which status an added torrent is.
 type StatusCode int
 
@@ -165,8 +244,236 @@ type Link struct {
 	Link string `json:"link,omitempty"`
 	// File name
 	Filename string `json:"filename,omitempty"`
-	// File size
-	Size int `json:"size,omitempty"`
+	// File size in bytes
+	Size int64 `json:"size,omitempty"`
 	// different format depending of version property
 	Files []interface{} `json:"files,omitempty"`
+	// Files decoded into a typed tree. Set when unmarshalling a Status, which contains the version.
+	// Nil if the files couldn't be parsed.
+	Tree []FileNode `json:"-"`
+}
+
+// FileNode is a file or folder in the file tree of a link, see Link.Tree.
+type FileNode struct {
+	// File or folder name
+	Name string
+	// File size in bytes. 0 for folders and if unknown.
+	Size int64
+	// Files and folders in the folder. Nil for files.
+	Children []FileNode
+}
+
+// IsDir reports whether the node is a folder.
+func (n FileNode) IsDir() bool {
+	return n.Children != nil
+}
+
+// Pin contains the PIN for AllDebrid's PIN authentication flow.
+type Pin struct {
+	// PIN that the user has to enter on the user URL
+	Pin string `json:"pin,omitempty"`
+	// Code for checking the PIN status
+	Check string `json:"check,omitempty"`
+	// Seconds until the PIN expires
+	ExpiresIn int `json:"expires_in,omitempty"`
+	// URL where the user has to enter the PIN, already containing the PIN
+	UserURL string `json:"user_url,omitempty"`
+	// URL where the user has to enter the PIN
+	BaseURL string `json:"base_url,omitempty"`
+	// URL for checking the PIN status
+	CheckURL string `json:"check_url,omitempty"`
+	// Point in time when the PIN expires. Calculated from ExpiresIn when receiving the PIN.
+	Expiry time.Time `json:"-"`
+}
+
+// PinStatus is the status of a PIN of AllDebrid's PIN authentication flow.
+type PinStatus struct {
+	// true if the user entered the PIN
+	Activated bool `json:"activated,omitempty"`
+	// Seconds until the PIN expires
+	ExpiresIn int `json:"expires_in,omitempty"`
+	// The user's API key. Only present when activated.
+	APIKey string `json:"apikey,omitempty"`
+}
+
+// Host is a hoster, streaming site or redirector that's supported by AllDebrid.
+type Host struct {
+	// Host name, like "uptobox"
+	Name string `json:"name,omitempty"`
+	// "premium" or "free"
+	Type string `json:"type,omitempty"`
+	// Domains of the host
+	Domains []string `json:"domains,omitempty"`
+	// Regular expressions of the supported links
+	Regexps []string `json:"regexps,omitempty"`
+	// Whether the host is currently working
+	Status bool `json:"status,omitempty"`
+	// Maximum number of simultaneous downloads
+	LimitSimuDl int `json:"limitSimuDl,omitempty"`
+	// !! Only present for hosts with quotas. Remaining quota of the user.
+	Quota int `json:"quota,omitempty"`
+	// !! Only present for hosts with quotas. Maximum quota.
+	QuotaMax int `json:"quotaMax,omitempty"`
+	// !! Only present for hosts with quotas. "traffic" (in MB) or "nb_download".
+	QuotaType string `json:"quotaType,omitempty"`
+}
+
+// Hosts contains the hosters, streaming sites and redirectors that are supported by AllDebrid, mapped by their name.
+type Hosts struct {
+	Hosts       map[string]Host `json:"hosts,omitempty"`
+	Streams     map[string]Host `json:"streams,omitempty"`
+	Redirectors map[string]Host `json:"redirectors,omitempty"`
+}
+
+// HostDomains contains the domains of the hosters, streaming sites and redirectors that are supported by AllDebrid.
+type HostDomains struct {
+	Hosts       []string `json:"hosts,omitempty"`
+	Streams     []string `json:"streams,omitempty"`
+	Redirectors []string `json:"redirectors,omitempty"`
+}
+
+// LinkInfo contains info about a link, as returned by getting its infos without unlocking it.
+type LinkInfo struct {
+	// Requested link, simplified if it was not in canonical form
+	Link string `json:"link,omitempty"`
+	// Link's file filename
+	Filename string `json:"filename,omitempty"`
+	// Link's file size in bytes
+	Size int64 `json:"size,omitempty"`
+	// Link host minified
+	Host string `json:"host,omitempty"`
+	// Matched host main domain
+	HostDomain string `json:"hostDomain,omitempty"`
+	// !! Only present if the link can't be processed
+	Error *Error `json:"error,omitempty"`
+}
+
+// Error is an error that's part of an otherwise successful AllDebrid response, like for a single link of many.
+type Error struct {
+	// Error code, like "LINK_DOWN"
+	Code string `json:"code,omitempty"`
+	// Human readable error message
+	Message string `json:"message,omitempty"`
+}
+
+// SavedLink is a link in the user's saved links or download history.
+type SavedLink struct {
+	// Saved link
+	Link string `json:"link,omitempty"`
+	// Link's file filename
+	Filename string `json:"filename,omitempty"`
+	// Link's file size in bytes
+	Size int64 `json:"size,omitempty"`
+	// Date when the link was saved or unlocked
+	Date time.Time `json:"date,omitempty"`
+	// Link host minified
+	Host string `json:"host,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the timestamp.
+func (l *SavedLink) UnmarshalJSON(data []byte) error {
+	type savedLink SavedLink
+	raw := struct {
+		*savedLink
+		Date jsonutil.UnixTime `json:"date,omitempty"`
+	}{savedLink: (*savedLink)(l)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	l.Date = time.Time(raw.Date)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l SavedLink) MarshalJSON() ([]byte, error) {
+	type savedLink SavedLink
+	return json.Marshal(struct {
+		savedLink
+		Date jsonutil.UnixTime `json:"date"`
+	}{savedLink: savedLink(l), Date: jsonutil.UnixTime(l.Date)})
+}
+
+// Verif is the status of a verification that AllDebrid requires when the user logs in from a new location,
+// indicated by the error "AUTH_BLOCKED" and a verification token.
+type Verif struct {
+	// "waiting", "allowed" or "denied"
+	Verif string `json:"verif,omitempty"`
+	// Whether the verification email can be resent
+	Resendable bool `json:"resendable,omitempty"`
+	// The user's API key. Only present when allowed.
+	APIKey string `json:"apikey,omitempty"`
+}
+
+// DelayedStatus indicates in which status the generation of a delayed link is.
+type DelayedStatus int
+
+const (
+	DelayedStatus_Processing DelayedStatus = iota + 1
+	DelayedStatus_Ready
+	DelayedStatus_Error
+)
+
+// DelayedLink is the status of a link that needs time to generate, see Download.Delayed.
+type DelayedLink struct {
+	Status DelayedStatus `json:"status,omitempty"`
+	// Estimated time until the link is ready
+	TimeLeft time.Duration `json:"time_left,omitempty"`
+	// Download link. Only present when ready.
+	Link string `json:"link,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the seconds until the link is ready.
+func (l *DelayedLink) UnmarshalJSON(data []byte) error {
+	type delayedLink DelayedLink
+	raw := struct {
+		*delayedLink
+		TimeLeft jsonutil.Seconds `json:"time_left,omitempty"`
+	}{delayedLink: (*delayedLink)(l)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	l.TimeLeft = time.Duration(raw.TimeLeft)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l DelayedLink) MarshalJSON() ([]byte, error) {
+	type delayedLink DelayedLink
+	return json.Marshal(struct {
+		delayedLink
+		TimeLeft jsonutil.Seconds `json:"time_left"`
+	}{delayedLink: delayedLink(l), TimeLeft: jsonutil.Seconds(l.TimeLeft)})
+}
+
+// InstantAvailability contains info about the instant availability of a torrent.
+type InstantAvailability struct {
+	// Magnet as it was sent
+	Magnet string `json:"magnet,omitempty"`
+	// Normalized info hash, see debrid.NormalizeInfoHash
+	Hash string `json:"hash,omitempty"`
+	// Whether the torrent is instantly available
+	Instant bool `json:"instant,omitempty"`
+	// Cached files. Only present when instantly available. Use FlattenFiles to get their paths.
+	// Nil if the files couldn't be parsed.
+	Files []FileNode `json:"-"`
+	// !! Only present if the magnet couldn't be processed
+	Error *Error `json:"error,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the files into a typed tree, which is nil if the files can't be parsed.
+func (a *InstantAvailability) UnmarshalJSON(data []byte) error {
+	type instantAvailability InstantAvailability
+	raw := struct {
+		*instantAvailability
+		Files []interface{} `json:"files,omitempty"`
+	}{instantAvailability: (*instantAvailability)(a)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	// Like with Status, files in an unexpected format shouldn't make the whole response unusable, so they're left nil.
+	a.Files, _ = ParseFiles(raw.Files, 0)
+	return nil
 }
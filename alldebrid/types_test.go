@@ -0,0 +1,74 @@
+package alldebrid_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/alldebrid"
+)
+
+func TestUnmarshalTimes(t *testing.T) {
+	user := alldebrid.User{}
+	require.NoError(t, json.Unmarshal([]byte(`{"username":"foo","isPremium":true,"premiumUntil":1604756220}`), &user))
+	require.Equal(t, "foo", user.Username)
+	require.True(t, user.PremiumUntil.Equal(time.Unix(1604756220, 0)))
+
+	// 0 means not premium
+	user = alldebrid.User{}
+	require.NoError(t, json.Unmarshal([]byte(`{"username":"foo","isPremium":false,"premiumUntil":0}`), &user))
+	require.True(t, user.PremiumUntil.IsZero())
+
+	// Numbers as strings
+	delayed := alldebrid.DelayedLink{}
+	require.NoError(t, json.Unmarshal([]byte(`{"status":1,"time_left":"90"}`), &delayed))
+	require.Equal(t, alldebrid.DelayedStatus_Processing, delayed.Status)
+	require.Equal(t, 90*time.Second, delayed.TimeLeft)
+
+	// Sizes over 2 GB
+	status := alldebrid.Status{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"size":5000000000,"uploadDate":null,"links":[{"link":"a","filename":"a.mkv","size":5000000000,"files":["a.mkv"]}]}`), &status))
+	require.Equal(t, int64(5000000000), status.Size)
+	require.Equal(t, int64(5000000000), status.Links[0].Size)
+	require.True(t, status.UploadDate.IsZero())
+
+	require.Error(t, json.Unmarshal([]byte(`{"premiumUntil":"soon"}`), &user))
+}
+
+func TestUnmarshalStatusWithUnparsableFiles(t *testing.T) {
+	status := alldebrid.Status{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"version":2,"links":[{"link":"a","filename":"a.mkv","files":[42]},{"link":"b","filename":"b.mkv","files":[{"n":"b.mkv","s":123}]}]}`), &status))
+	require.Len(t, status.Links, 2)
+	// The link with unexpected files has no tree, but the other links are decoded as usual
+	require.Nil(t, status.Links[0].Tree)
+	require.Equal(t, "/a.mkv", status.Links[0].Path())
+	require.Equal(t, []alldebrid.FileNode{{Name: "b.mkv", Size: 123}}, status.Links[1].Tree)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tt := []struct {
+		name     string
+		json     string
+		newValue func() interface{}
+	}{
+		{"User", `{"username":"foo","isPremium":true,"premiumUntil":1604756220,"limitedHostersQuotas":{"a.com":1000}}`, func() interface{} { return &alldebrid.User{} }},
+		{"User not premium", `{"username":"foo","isPremium":false,"premiumUntil":0}`, func() interface{} { return &alldebrid.User{} }},
+		{"Status", `{"id":1,"filename":"a","size":5000000000,"status":"Ready","statusCode":4,"uploadDate":1604756220,"completionDate":1604756280,"version":2,"links":[{"link":"a","filename":"a.mkv","size":123,"files":[{"n":"Folder","e":[{"n":"a.mkv","s":123}]}]}]}`, func() interface{} { return &alldebrid.Status{} }},
+		{"Status not completed", `{"id":1,"status":"Downloading","statusCode":1,"uploadDate":1604756220,"completionDate":0}`, func() interface{} { return &alldebrid.Status{} }},
+		{"SavedLink", `{"link":"a","filename":"a.mkv","size":123,"date":1604756220,"host":"a.com"}`, func() interface{} { return &alldebrid.SavedLink{} }},
+		{"DelayedLink", `{"status":1,"time_left":"90.5"}`, func() interface{} { return &alldebrid.DelayedLink{} }},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := tc.newValue()
+			require.NoError(t, json.Unmarshal([]byte(tc.json), v))
+			data, err := json.Marshal(v)
+			require.NoError(t, err)
+			got := tc.newValue()
+			require.NoError(t, json.Unmarshal(data, got))
+			require.Equal(t, v, got)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package alldebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// GetSavedLinks fetches and returns the user's saved links.
+func (c *Client) GetSavedLinks(ctx context.Context) ([]SavedLink, error) {
+	c.logger.Debug("Getting saved links...", zapDebridService)
+
+	links, err := c.getLinks(ctx, c.opts.BaseURL+"/user/links")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get saved links: %w", err)
+	}
+
+	c.logger.Debug("Got saved links", zap.String("links", fmt.Sprintf("%+v", links)), zapDebridService)
+	return links, nil
+}
+
+// SaveLinks saves links to the user's saved links.
+func (c *Client) SaveLinks(ctx context.Context, links ...string) error {
+	c.logger.Debug("Saving links...", zapDebridService)
+
+	data := url.Values{"links[]": links}
+	if err := c.getSuccess(ctx, c.opts.BaseURL+"/user/links/save?"+data.Encode()); err != nil {
+		return fmt.Errorf("couldn't save links: %w", err)
+	}
+
+	c.logger.Debug("Saved links", zapDebridService)
+	return nil
+}
+
+// DeleteSavedLinks deletes links from the user's saved links.
+func (c *Client) DeleteSavedLinks(ctx context.Context, links ...string) error {
+	c.logger.Debug("Deleting saved links...", zapDebridService)
+
+	data := url.Values{"links[]": links}
+	if err := c.getSuccess(ctx, c.opts.BaseURL+"/user/links/delete?"+data.Encode()); err != nil {
+		return fmt.Errorf("couldn't delete saved links: %w", err)
+	}
+
+	c.logger.Debug("Deleted saved links", zapDebridService)
+	return nil
+}
+
+// GetHistory fetches and returns the links the user recently unlocked.
+// The history is only recorded if the user enabled it in the account settings.
+func (c *Client) GetHistory(ctx context.Context) ([]SavedLink, error) {
+	c.logger.Debug("Getting history...", zapDebridService)
+
+	links, err := c.getLinks(ctx, c.opts.BaseURL+"/user/history")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get history: %w", err)
+	}
+
+	c.logger.Debug("Got history", zap.String("links", fmt.Sprintf("%+v", links)), zapDebridService)
+	return links, nil
+}
+
+// DeleteHistory deletes the user's history of unlocked links.
+func (c *Client) DeleteHistory(ctx context.Context) error {
+	c.logger.Debug("Deleting history...", zapDebridService)
+
+	if err := c.getSuccess(ctx, c.opts.BaseURL+"/user/history/delete"); err != nil {
+		return fmt.Errorf("couldn't delete history: %w", err)
+	}
+
+	c.logger.Debug("Deleted history", zapDebridService)
+	return nil
+}
+
+// GetVerif fetches and returns the status of the verification that AllDebrid requires when the user logs in from a new location.
+// The token is the one from the "AUTH_BLOCKED" error response. The client doesn't require an API key for this.
+func (c *Client) GetVerif(ctx context.Context, token string) (Verif, error) {
+	c.logger.Debug("Getting verification status...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/user/verif?token="+url.QueryEscape(token))
+	if err != nil {
+		return Verif{}, fmt.Errorf("couldn't get verification status: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return Verif{}, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	verifJSON := gjson.GetBytes(resBytes, "data").Raw
+	verif := Verif{}
+	if err = json.Unmarshal([]byte(verifJSON), &verif); err != nil {
+		return Verif{}, fmt.Errorf("couldn't unmarshal verification status: %w", err)
+	}
+
+	c.logger.Debug("Got verification status", zap.String("verif", verif.Verif), zapDebridService)
+	return verif, nil
+}
+
+// ResendVerif resends the verification email, if Verif.Resendable is true.
+func (c *Client) ResendVerif(ctx context.Context, token string) error {
+	c.logger.Debug("Resending verification email...", zapDebridService)
+
+	if err := c.getSuccess(ctx, c.opts.BaseURL+"/user/verif/resend?token="+url.QueryEscape(token)); err != nil {
+		return fmt.Errorf("couldn't resend verification email: %w", err)
+	}
+
+	c.logger.Debug("Resent verification email", zapDebridService)
+	return nil
+}
+
+func (c *Client) getLinks(ctx context.Context, url string) ([]SavedLink, error) {
+	resBytes, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		errorCode := gjson.GetBytes(resBytes, "error.message").String()
+		return nil, fmt.Errorf("got error response from AllDebrid: %v", errorCode)
+	}
+	links := []SavedLink{}
+	if linksJSON := gjson.GetBytes(resBytes, "data.links"); linksJSON.Exists() {
+		if err = json.Unmarshal([]byte(linksJSON.Raw), &links); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal links: %w", err)
+		}
+	}
+	return links, nil
+}
@@ -1,21 +1,62 @@
 package alldebrid
 
-import "errors"
+import (
+	"fmt"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
 
 // SelectLargestFile returns the link of the largest file in the torrent.
+//
+// Deprecated: Use SelectFile with debrid.LargestFile, or a more specific selector like debrid.DefaultFileSelector.
 func SelectLargestFile(status Status) (Link, error) {
-	var largestLink Link
-	largestSize := 0
+	return SelectFile(status, debrid.LargestFile)
+}
 
-	for _, link := range status.Links {
-		if link.Size > largestSize {
-			largestLink = link
-			largestSize = link.Size
-		}
+// SelectFile returns the link of the file in the torrent that the selector selects.
+// The selector gets the full paths of the files (see Link.Path), so it can for example exclude files in a "Sample" folder.
+func SelectFile(status Status, selector debrid.FileSelector) (Link, error) {
+	files := make([]debrid.File, len(status.Links))
+	for i, link := range status.Links {
+		files[i] = debrid.File{Path: link.Path(), Size: link.Size}
 	}
-	if largestSize == 0 {
-		return Link{}, errors.New("couldn't find largest file in status")
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return Link{}, fmt.Errorf("couldn't select file in status: %w", err)
 	}
+	return status.Links[i], nil
+}
+
+// StreamConstraints are constraints for selecting one of the alternative streams of an unlocked link with SelectStream.
+// Zero values mean no constraint.
+type StreamConstraints struct {
+	// Maximum resolution, e.g. 720 for 720p
+	MaxQuality int
+	// Preferred file extension, e.g. "mp4". Streams with other extensions are only selected if there's no stream with this extension in the best quality.
+	PreferredExt string
+	// Maximum file size in bytes. Streams with unknown file size are not excluded.
+	MaxFilesize int64
+}
 
-	return largestLink, nil
+// SelectStream returns the stream with the highest quality among the ones that satisfy the constraints.
+// The boolean return value is false if no stream satisfies the constraints.
+// The selected stream can be resolved to a direct link with Client.GetStreamingLink.
+func SelectStream(streams []Stream, constraints StreamConstraints) (Stream, bool) {
+	best := -1
+	for i, stream := range streams {
+		if constraints.MaxQuality != 0 && stream.Quality > constraints.MaxQuality {
+			continue
+		}
+		if constraints.MaxFilesize != 0 && stream.Filesize > constraints.MaxFilesize {
+			continue
+		}
+		if best == -1 || stream.Quality > streams[best].Quality ||
+			(stream.Quality == streams[best].Quality && stream.Ext == constraints.PreferredExt && streams[best].Ext != constraints.PreferredExt) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return Stream{}, false
+	}
+	return streams[best], true
 }
@@ -0,0 +1,36 @@
+package alldebrid_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/alldebrid"
+)
+
+func TestSelectStream(t *testing.T) {
+	streams := []alldebrid.Stream{
+		{ID: "a", Quality: 360, Ext: "mp4", Filesize: 100},
+		{ID: "b", Quality: 720, Ext: "webm", Filesize: 400},
+		{ID: "c", Quality: 720, Ext: "mp4", Filesize: 500},
+		{ID: "d", Quality: 1080, Ext: "mp4", Filesize: 1000},
+	}
+	tests := []struct {
+		name        string
+		constraints alldebrid.StreamConstraints
+		expectedID  string
+	}{
+		{"no constraints", alldebrid.StreamConstraints{}, "d"},
+		{"max quality", alldebrid.StreamConstraints{MaxQuality: 720}, "b"},
+		{"preferred ext", alldebrid.StreamConstraints{MaxQuality: 720, PreferredExt: "mp4"}, "c"},
+		{"max file size", alldebrid.StreamConstraints{MaxFilesize: 450}, "b"},
+		{"none satisfied", alldebrid.StreamConstraints{MaxQuality: 240}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stream, ok := alldebrid.SelectStream(streams, test.constraints)
+			require.Equal(t, test.expectedID != "", ok)
+			require.Equal(t, test.expectedID, stream.ID)
+		})
+	}
+}
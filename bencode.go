@@ -0,0 +1,150 @@
+package debrid
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrorInvalidBencode is returned when data can't be decoded as bencode.
+var ErrorInvalidBencode = errors.New("invalid bencode")
+
+// maxBencodeDepth is the maximum nesting depth of lists and dictionaries.
+// Torrent files only need a few levels, and limiting it prevents deeply nested data from overflowing the stack.
+const maxBencodeDepth = 64
+
+// bdecoder decodes bencoded data into int64, string, []interface{} and map[string]interface{} values.
+// It also records where the value of the top-level "info" key starts and ends, because the info hash of a torrent is the SHA-1 hash of exactly these bytes.
+type bdecoder struct {
+	data  []byte
+	pos   int
+	depth int
+
+	infoStart int
+	infoEnd   int
+}
+
+func decodeBencode(data []byte) (interface{}, *bdecoder, error) {
+	d := &bdecoder{data: data}
+	v, err := d.decode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrorInvalidBencode, err)
+	}
+	if d.pos != len(d.data) {
+		return nil, nil, fmt.Errorf("%w: trailing data at offset %v", ErrorInvalidBencode, d.pos)
+	}
+	return v, d, nil
+}
+
+func (d *bdecoder) decode() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, errors.New("unexpected end of data")
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		d.pos++
+		return d.decodeInt('e')
+	case c == 'l':
+		d.pos++
+		return d.decodeList()
+	case c == 'd':
+		d.pos++
+		return d.decodeDict()
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+	default:
+		return nil, fmt.Errorf("unexpected character %q at offset %v", c, d.pos)
+	}
+}
+
+func (d *bdecoder) decodeInt(delim byte) (int64, error) {
+	start := d.pos
+	for d.pos < len(d.data) && d.data[d.pos] != delim {
+		d.pos++
+	}
+	if d.pos >= len(d.data) {
+		return 0, errors.New("unterminated integer")
+	}
+	i, err := strconv.ParseInt(string(d.data[start:d.pos]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer at offset %v: %w", start, err)
+	}
+	// Skip delimiter
+	d.pos++
+	return i, nil
+}
+
+func (d *bdecoder) decodeString() (string, error) {
+	start := d.pos
+	length, err := d.decodeInt(':')
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int64(len(d.data)-d.pos) < length {
+		return "", fmt.Errorf("invalid string length %v at offset %v", length, start)
+	}
+	s := string(d.data[d.pos : d.pos+int(length)])
+	d.pos += int(length)
+	return s, nil
+}
+
+func (d *bdecoder) decodeList() ([]interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+
+	list := []interface{}{}
+	for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	if d.pos >= len(d.data) {
+		return nil, errors.New("unterminated list")
+	}
+	// Skip 'e'
+	d.pos++
+	return list, nil
+}
+
+func (d *bdecoder) decodeDict() (map[string]interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+
+	dict := map[string]interface{}{}
+	for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+		key, err := d.decodeString()
+		if err != nil {
+			return nil, fmt.Errorf("invalid dictionary key: %w", err)
+		}
+		start := d.pos
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if d.depth == 1 && key == "info" {
+			d.infoStart, d.infoEnd = start, d.pos
+		}
+		dict[key] = v
+	}
+	if d.pos >= len(d.data) {
+		return nil, errors.New("unterminated dictionary")
+	}
+	// Skip 'e'
+	d.pos++
+	return dict, nil
+}
+
+// enter increases the depth when starting to decode a list or dictionary, and fails if the maximum depth is exceeded.
+func (d *bdecoder) enter() error {
+	if d.depth >= maxBencodeDepth {
+		return fmt.Errorf("nesting deeper than %v levels at offset %v", maxBencodeDepth, d.pos)
+	}
+	d.depth++
+	return nil
+}
@@ -0,0 +1,113 @@
+package debrid
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Episode is the episode info that's parsed from a file path by ParseEpisode.
+type Episode struct {
+	// Season number, 0 if unknown
+	Season int
+	// Episode number within the season, 0 if unknown
+	Episode int
+	// Last episode number for files that contain multiple episodes, like "S01E01-E02". Same as Episode otherwise.
+	LastEpisode int
+	// Absolute episode number as often used for anime, 0 if unknown
+	Absolute int
+}
+
+var (
+	// "S02E07", "s02.e07", "S02E07E08", "S02E07-E08", "S02E07-08"
+	seasonEpisodeRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])s(\d{1,2})[ ._-]?e(\d{1,3})(?:(?:[ ._-]?e|-)(\d{1,3}))?(?:[^0-9a-z]|$)`)
+	// "2x07", "2x07-2x08"
+	crossEpisodeRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(\d{1,2})x(\d{2,3})(?:-(?:\d{1,2}x)?(\d{2,3}))?(?:[^0-9a-z]|$)`)
+	// "Episode 7", "Ep.07". A bare "E" isn't enough, because it's also part of names like "x265-e10" or "Vol.E2".
+	episodeRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:episode|ep)[ ._-]?(\d{1,3})(?:[^0-9]|$)`)
+	// "[Group] Show - 07 [1080p].mkv", "Show - 1007v2.mkv". Four digit numbers starting with 19 or 20 are years, like in "Movie - 2019.mkv".
+	absoluteEpisodeRegex = regexp.MustCompile(`(?i) - (\d{1,3}|(?:[03-9]\d|1[0-8]|2[1-9])\d{2})(?:v\d)?(?:[ ._\[(-]|$)`)
+	// "Season 2", "Season.02", "S02" as directory name
+	seasonDirRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:season|s)[ ._-]?(\d{1,2})(?:[^0-9]|$)`)
+)
+
+// ParseEpisode parses the season and episode number from a file path, for example a file in a season pack.
+// It supports the formats "S02E07" (including multi-episode files like "S02E07E08"), "2x07", "Episode 7" and absolute numbering like "Show - 107".
+// For formats without season number, the season is taken from a directory like "Season 2" if possible.
+// The boolean return value signals whether an episode number was found.
+func ParseEpisode(filePath string) (Episode, bool) {
+	dir, name := path.Split(filePath)
+	name = strings.TrimSuffix(name, path.Ext(name))
+
+	if m := seasonEpisodeRegex.FindStringSubmatch(name); m != nil {
+		return newEpisode(m[1], m[2], m[3]), true
+	}
+	if m := crossEpisodeRegex.FindStringSubmatch(name); m != nil {
+		return newEpisode(m[1], m[2], m[3]), true
+	}
+
+	var season string
+	if m := seasonDirRegex.FindStringSubmatch(dir); m != nil {
+		season = m[1]
+	}
+	if m := episodeRegex.FindStringSubmatch(name); m != nil {
+		return newEpisode(season, m[1], ""), true
+	}
+	if m := absoluteEpisodeRegex.FindStringSubmatch(name); m != nil {
+		absolute, _ := strconv.Atoi(m[1])
+		return Episode{Absolute: absolute}, true
+	}
+	return Episode{}, false
+}
+
+func newEpisode(season, episode, lastEpisode string) Episode {
+	e := Episode{}
+	e.Season, _ = strconv.Atoi(season)
+	e.Episode, _ = strconv.Atoi(episode)
+	e.LastEpisode, _ = strconv.Atoi(lastEpisode)
+	if e.LastEpisode < e.Episode {
+		e.LastEpisode = e.Episode
+	}
+	return e
+}
+
+// Contains reports whether the parsed episode info matches the given season and episode.
+// An unknown season matches any season, which is common for season packs where only the episode number is part of the file name.
+func (e Episode) Contains(season, episode int) bool {
+	if e.Episode == 0 {
+		return false
+	}
+	if e.Season != 0 && e.Season != season {
+		return false
+	}
+	return episode >= e.Episode && episode <= e.LastEpisode
+}
+
+// MatchEpisode returns a FileFilter that matches files containing the given episode, see Episode.Contains.
+func MatchEpisode(season, episode int) FileFilter {
+	return func(file File) bool {
+		e, ok := ParseEpisode(file.Path)
+		return ok && e.Contains(season, episode)
+	}
+}
+
+// MatchAbsoluteEpisode returns a FileFilter that matches files with the given absolute episode number.
+func MatchAbsoluteEpisode(absolute int) FileFilter {
+	return func(file File) bool {
+		e, ok := ParseEpisode(file.Path)
+		return ok && e.Absolute == absolute
+	}
+}
+
+// EpisodeSelector returns a FileSelector that selects the file of the given episode from a season pack.
+// If multiple files match (for example the episode and its sample), DefaultFileSelector is used to choose between them.
+func EpisodeSelector(season, episode int) FileSelector {
+	return Filtered(DefaultFileSelector, MatchEpisode(season, episode))
+}
+
+// AbsoluteEpisodeSelector returns a FileSelector that selects the file with the given absolute episode number, as often used for anime.
+// If multiple files match, DefaultFileSelector is used to choose between them.
+func AbsoluteEpisodeSelector(absolute int) FileSelector {
+	return Filtered(DefaultFileSelector, MatchAbsoluteEpisode(absolute))
+}
@@ -0,0 +1,65 @@
+package debrid_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
+
+func TestParseEpisode(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected debrid.Episode
+		ok       bool
+	}{
+		{"/Show.S02.1080p.WEB-DL/Show.S02E07.1080p.WEB-DL.x264-GROUP.mkv", debrid.Episode{Season: 2, Episode: 7, LastEpisode: 7}, true},
+		{"/Show.s02.e07.720p.mkv", debrid.Episode{Season: 2, Episode: 7, LastEpisode: 7}, true},
+		{"/Show.S01E01E02.mkv", debrid.Episode{Season: 1, Episode: 1, LastEpisode: 2}, true},
+		{"/Show.S01E01-E02.mkv", debrid.Episode{Season: 1, Episode: 1, LastEpisode: 2}, true},
+		{"/Show.S01E05-720p.mkv", debrid.Episode{Season: 1, Episode: 5, LastEpisode: 5}, true},
+		{"/Show 1x05 Title.avi", debrid.Episode{Season: 1, Episode: 5, LastEpisode: 5}, true},
+		{"/Show.1920x1080.mkv", debrid.Episode{}, false},
+		{"/Show/Season 2/Episode 7.mkv", debrid.Episode{Season: 2, Episode: 7, LastEpisode: 7}, true},
+		{"/Show/Ep.07.mkv", debrid.Episode{Episode: 7, LastEpisode: 7}, true},
+		{"/[Group] Show - 107 [1080p].mkv", debrid.Episode{Absolute: 107}, true},
+		{"/[Group] Show - 07v2 (1080p).mkv", debrid.Episode{Absolute: 7}, true},
+		{"/Movie.2020.1080p.BluRay.mkv", debrid.Episode{}, false},
+		{"/Movie - 2019.mkv", debrid.Episode{}, false},
+		{"/Movie - 1999 [1080p].mkv", debrid.Episode{}, false},
+		{"/[Group] Show - 1007v2.mkv", debrid.Episode{Absolute: 1007}, true},
+		{"/Movie.2020.1080p.x265-e10.mkv", debrid.Episode{}, false},
+		{"/Comic.Vol.E2.mkv", debrid.Episode{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			episode, ok := debrid.ParseEpisode(test.path)
+			require.Equal(t, test.ok, ok)
+			require.Equal(t, test.expected, episode)
+		})
+	}
+}
+
+func TestEpisodeSelector(t *testing.T) {
+	files := []debrid.File{
+		{Path: "/Show.S02/Show.S02E06.mkv", Size: 1000},
+		{Path: "/Show.S02/Show.S02E07.mkv", Size: 900},
+		{Path: "/Show.S02/Sample/Show.S02E07.sample.mkv", Size: 50},
+		{Path: "/Show.S02/Show.S02E08.mkv", Size: 1100},
+	}
+	i, err := debrid.EpisodeSelector(2, 7).SelectFile(files)
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+
+	_, err = debrid.EpisodeSelector(3, 7).SelectFile(files)
+	require.ErrorIs(t, err, debrid.ErrorNoMatchingFile)
+
+	files = []debrid.File{
+		{Path: "/[Group] Show - 106 [1080p].mkv", Size: 1000},
+		{Path: "/[Group] Show - 107 [1080p].mkv", Size: 900},
+	}
+	i, err = debrid.AbsoluteEpisodeSelector(107).SelectFile(files)
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+}
@@ -0,0 +1,79 @@
+// Package fakeserver provides a fake HTTP server with canned responses, for testing the debrid service clients without network access.
+package fakeserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Response is a canned response of the fake server.
+type Response struct {
+	// HTTP status code. Defaults to 200 OK.
+	Status int
+	Body   string
+	// If set, it's used instead of Body, with one element per request and the last one being repeated.
+	Bodies []string
+	// If set, its return value is used instead of Body.
+	Respond func(r *http.Request) string
+	// If set, it's called with the request before responding.
+	// It runs in the server's goroutine, so it must use assert instead of require.
+	Check func(t *testing.T, r *http.Request)
+}
+
+// KeyFunc returns the key of the response for a request.
+type KeyFunc func(r *http.Request) string
+
+// ByPath uses the request path as key, like "/hosts".
+func ByPath(r *http.Request) string {
+	return r.URL.Path
+}
+
+// ByMethodAndPath uses the request method and path as key, like "GET /hosts".
+func ByMethodAndPath(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// New starts a fake server that responds to requests with the responses that the key function selects.
+// If checkAuth is set, it's called with every request, for checking the credentials.
+// The server is closed when the test finishes.
+func New(t *testing.T, key KeyFunc, checkAuth func(t *testing.T, r *http.Request), responses map[string]Response) *httptest.Server {
+	var lock sync.Mutex
+	requestCounts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if checkAuth != nil {
+			checkAuth(t, r)
+		}
+		k := key(r)
+		res, found := responses[k]
+		if !found {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if res.Check != nil {
+			res.Check(t, r)
+		}
+		body := res.Body
+		if len(res.Bodies) > 0 {
+			lock.Lock()
+			i := requestCounts[k]
+			requestCounts[k]++
+			lock.Unlock()
+			if i >= len(res.Bodies) {
+				i = len(res.Bodies) - 1
+			}
+			body = res.Bodies[i]
+		}
+		if res.Respond != nil {
+			body = res.Respond(r)
+		}
+		if res.Status != 0 {
+			w.WriteHeader(res.Status)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,109 @@
+// Package jsonutil provides lenient JSON types for the numbers, timestamps and durations that the debrid services send in inconsistent formats.
+// All of them accept numbers as JSON numbers or strings, and decode null, false and empty strings as zero value.
+// They're meant to be used in the UnmarshalJSON and MarshalJSON methods of the services' types.
+// MarshalJSON writes the format that the service sends, so that marshalled values can be unmarshalled again.
+package jsonutil
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Unquote returns the JSON value without surrounding whitespace and quotes.
+// It returns an empty string for null, false and empty strings, which the debrid services send instead of zero values.
+func Unquote(data []byte) string {
+	s := string(bytes.Trim(bytes.TrimSpace(data), `"`))
+	if s == "null" || s == "false" {
+		return ""
+	}
+	return s
+}
+
+// Int is an integer that's sometimes sent as string or as floating point number.
+// Floating point numbers are rounded to the nearest integer.
+type Int int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *Int) UnmarshalJSON(data []byte) error {
+	s := Unquote(data)
+	if s == "" {
+		*i = 0
+		return nil
+	}
+	// Parsing as integer first keeps the precision of integers that don't fit into a float64
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*i = Int(n)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("couldn't parse number %s: %w", data, err)
+	}
+	*i = Int(math.Round(f))
+	return nil
+}
+
+// Float is a floating point number that's sometimes sent as string.
+type Float float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Float) UnmarshalJSON(data []byte) error {
+	s := Unquote(data)
+	if s == "" {
+		*f = 0
+		return nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("couldn't parse number %s: %w", data, err)
+	}
+	*f = Float(n)
+	return nil
+}
+
+// UnixTime is a Unix timestamp in seconds. 0 means there's no point in time and is decoded as zero time.Time.
+type UnixTime time.Time
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	var sec Int
+	if err := sec.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if sec == 0 {
+		*t = UnixTime{}
+		return nil
+	}
+	*t = UnixTime(time.Unix(int64(sec), 0))
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// Zero time.Time is encoded as 0.
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	if time.Time(t).IsZero() {
+		return []byte("0"), nil
+	}
+	return []byte(strconv.FormatInt(time.Time(t).Unix(), 10)), nil
+}
+
+// Seconds is a duration in seconds, which can be a floating point number.
+type Seconds time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Seconds) UnmarshalJSON(data []byte) error {
+	var sec Float
+	if err := sec.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*s = Seconds(math.Round(float64(sec) * float64(time.Second)))
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Seconds) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(time.Duration(s).Seconds(), 'f', -1, 64)), nil
+}
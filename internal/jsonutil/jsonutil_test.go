@@ -0,0 +1,72 @@
+package jsonutil_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/internal/jsonutil"
+)
+
+func TestLenientValues(t *testing.T) {
+	tt := []struct {
+		json    string
+		int     jsonutil.Int
+		float   jsonutil.Float
+		seconds time.Duration
+	}{
+		{`123`, 123, 123, 123 * time.Second},
+		{`"123"`, 123, 123, 123 * time.Second},
+		{`1.5`, 2, 1.5, 1500 * time.Millisecond},
+		{`"1.4"`, 1, 1.4, 1400 * time.Millisecond},
+		{`null`, 0, 0, 0},
+		{`false`, 0, 0, 0},
+		{`""`, 0, 0, 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.json, func(t *testing.T) {
+			var i jsonutil.Int
+			require.NoError(t, json.Unmarshal([]byte(tc.json), &i))
+			require.Equal(t, tc.int, i)
+			var f jsonutil.Float
+			require.NoError(t, json.Unmarshal([]byte(tc.json), &f))
+			require.Equal(t, tc.float, f)
+			var s jsonutil.Seconds
+			require.NoError(t, json.Unmarshal([]byte(tc.json), &s))
+			require.Equal(t, tc.seconds, time.Duration(s))
+		})
+	}
+
+	// Too large for a float64 without losing precision
+	var i jsonutil.Int
+	require.NoError(t, json.Unmarshal([]byte(`9007199254740993`), &i))
+	require.Equal(t, jsonutil.Int(9007199254740993), i)
+
+	require.Error(t, json.Unmarshal([]byte(`"soon"`), &i))
+}
+
+func TestUnixTime(t *testing.T) {
+	var ut jsonutil.UnixTime
+	require.NoError(t, json.Unmarshal([]byte(`"1604756220"`), &ut))
+	require.True(t, time.Time(ut).Equal(time.Unix(1604756220, 0)))
+	data, err := json.Marshal(ut)
+	require.NoError(t, err)
+	require.Equal(t, `1604756220`, string(data))
+
+	// 0 and false mean there's no point in time
+	for _, s := range []string{`0`, `false`} {
+		require.NoError(t, json.Unmarshal([]byte(s), &ut))
+		require.True(t, time.Time(ut).IsZero())
+	}
+	data, err = json.Marshal(jsonutil.UnixTime{})
+	require.NoError(t, err)
+	require.Equal(t, `0`, string(data))
+}
+
+func TestSecondsMarshal(t *testing.T) {
+	data, err := json.Marshal(jsonutil.Seconds(5400500 * time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, `5400.5`, string(data))
+}
@@ -0,0 +1,143 @@
+package debrid
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrorInvalidInfoHash is returned when a string is neither a hex nor a base32 encoded BitTorrent v1 info hash.
+var ErrorInvalidInfoHash = errors.New("invalid info hash")
+
+// ErrorInvalidMagnet is returned when a string can't be parsed as magnet URI or doesn't contain a BitTorrent info hash.
+var ErrorInvalidMagnet = errors.New("invalid magnet URI")
+
+const btihPrefix = "urn:btih:"
+
+// Magnet represents the parts of a magnet URI that are relevant for debrid services.
+type Magnet struct {
+	// Normalized info hash, see NormalizeInfoHash
+	InfoHash string
+	// Display name ("dn")
+	Name string
+	// Tracker URLs ("tr")
+	Trackers []string
+	// Exact length in bytes ("xl"), 0 if unknown
+	Length int64
+}
+
+// NormalizeInfoHash validates a BitTorrent v1 info hash and returns it in the form that all clients of this library use for cache keys and result maps:
+// 40 hexadecimal characters in upper case.
+// The hash can be hex encoded (40 characters, any case) or base32 encoded (32 characters, as sometimes used in magnet URIs).
+func NormalizeInfoHash(hash string) (string, error) {
+	hash = strings.TrimSpace(hash)
+	switch len(hash) {
+	case 40:
+		if _, err := hex.DecodeString(hash); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrorInvalidInfoHash, err)
+		}
+		return strings.ToUpper(hash), nil
+	case 32:
+		b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrorInvalidInfoHash, err)
+		}
+		return strings.ToUpper(hex.EncodeToString(b)), nil
+	default:
+		return "", fmt.Errorf("%w: unexpected length %v", ErrorInvalidInfoHash, len(hash))
+	}
+}
+
+// IsValidInfoHash returns true if the hash can be normalized by NormalizeInfoHash.
+func IsValidInfoHash(hash string) bool {
+	_, err := NormalizeInfoHash(hash)
+	return err == nil
+}
+
+// ParseMagnet parses a magnet URI.
+// Only BitTorrent magnets ("xt=urn:btih:...") are supported. If a magnet has multiple "xt" params, the first BitTorrent one is used.
+func ParseMagnet(uri string) (Magnet, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return Magnet{}, fmt.Errorf("%w: %v", ErrorInvalidMagnet, err)
+	}
+	if u.Scheme != "magnet" {
+		return Magnet{}, fmt.Errorf("%w: unexpected scheme %q", ErrorInvalidMagnet, u.Scheme)
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return Magnet{}, fmt.Errorf("%w: %v", ErrorInvalidMagnet, err)
+	}
+
+	m := Magnet{
+		Name:     query.Get("dn"),
+		Trackers: query["tr"],
+	}
+	for _, xt := range query["xt"] {
+		if len(xt) > len(btihPrefix) && strings.EqualFold(xt[:len(btihPrefix)], btihPrefix) {
+			if m.InfoHash, err = NormalizeInfoHash(xt[len(btihPrefix):]); err != nil {
+				return Magnet{}, fmt.Errorf("%w: %v", ErrorInvalidMagnet, err)
+			}
+			break
+		}
+	}
+	if m.InfoHash == "" {
+		return Magnet{}, fmt.Errorf("%w: no BitTorrent info hash", ErrorInvalidMagnet)
+	}
+	if xl := query.Get("xl"); xl != "" {
+		if m.Length, err = strconv.ParseInt(xl, 10, 64); err != nil {
+			return Magnet{}, fmt.Errorf("%w: invalid exact length: %v", ErrorInvalidMagnet, err)
+		}
+	}
+
+	return m, nil
+}
+
+// ParseInfoHash returns the normalized info hash of a string that's either an info hash or a magnet URI.
+// Some debrid APIs accept both, so this is useful to map their responses back to the request.
+func ParseInfoHash(hashOrMagnet string) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(hashOrMagnet), "magnet:") {
+		m, err := ParseMagnet(hashOrMagnet)
+		if err != nil {
+			return "", err
+		}
+		return m.InfoHash, nil
+	}
+	return NormalizeInfoHash(hashOrMagnet)
+}
+
+// BuildMagnet creates a magnet URI from an info hash, an optional display name and optional trackers.
+func BuildMagnet(infoHash, name string, trackers ...string) (string, error) {
+	infoHash, err := NormalizeInfoHash(infoHash)
+	if err != nil {
+		return "", err
+	}
+	m := Magnet{
+		InfoHash: infoHash,
+		Name:     name,
+		Trackers: trackers,
+	}
+	return m.String(), nil
+}
+
+// String returns the magnet URI.
+// The info hash is used as is, so the Magnet should be created by ParseMagnet or have a normalized info hash.
+func (m Magnet) String() string {
+	// Not using url.Values.Encode(), because it sorts by key and we want "xt" first, as most magnets in the wild have it.
+	var sb strings.Builder
+	sb.WriteString("magnet:?xt=" + btihPrefix + m.InfoHash)
+	if m.Name != "" {
+		sb.WriteString("&dn=" + url.QueryEscape(m.Name))
+	}
+	if m.Length > 0 {
+		sb.WriteString("&xl=" + strconv.FormatInt(m.Length, 10))
+	}
+	for _, tracker := range m.Trackers {
+		sb.WriteString("&tr=" + url.QueryEscape(tracker))
+	}
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package debrid_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
+
+// Night of the Living Dead, 1968, public domain (so legal to download, stream and share), from YTS
+var (
+	nightOfTheLivingDeadHash   = "50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139"
+	nightOfTheLivingDeadMagnet = "magnet:?xt=urn:btih:50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139&dn=Night+of+the+Living+Dead+%281968%29+%5B720p%5D+%5BYTS.MX%5D&tr=udp%3A%2F%2Ftracker.opentrackr.org%3A1337%2Fannounce&tr=udp%3A%2F%2Ftracker.leechers-paradise.org%3A6969%2Fannounce&tr=udp%3A%2F%2F9.rarbg.to%3A2710%2Fannounce&tr=udp%3A%2F%2Fp4p.arenabg.ch%3A1337%2Fannounce&tr=udp%3A%2F%2Ftracker.cyberia.is%3A6969%2Fannounce&tr=http%3A%2F%2Fp4p.arenabg.com%3A1337%2Fannounce&tr=udp%3A%2F%2Ftracker.internetwarriors.net%3A1337%2Fannounce"
+)
+
+func TestNormalizeInfoHash(t *testing.T) {
+	// Hex, upper and lower case
+	hash, err := debrid.NormalizeInfoHash(nightOfTheLivingDeadHash)
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadHash, hash)
+	hash, err = debrid.NormalizeInfoHash("50b7dafb7137cbecf045f78e8efbe4ac1a90d139")
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadHash, hash)
+
+	// Base32
+	hash, err = debrid.NormalizeInfoHash("KC35V63RG7F6Z4CF66HI567EVQNJBUJZ")
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadHash, hash)
+
+	// Invalid
+	_, err = debrid.NormalizeInfoHash("50B7DAFB7137CBECF045F78E8EFBE4AC1A90D13")
+	require.ErrorIs(t, err, debrid.ErrorInvalidInfoHash)
+	_, err = debrid.NormalizeInfoHash("ZZB7DAFB7137CBECF045F78E8EFBE4AC1A90D139")
+	require.ErrorIs(t, err, debrid.ErrorInvalidInfoHash)
+}
+
+func TestParseMagnet(t *testing.T) {
+	m, err := debrid.ParseMagnet(nightOfTheLivingDeadMagnet)
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadHash, m.InfoHash)
+	require.Equal(t, "Night of the Living Dead (1968) [720p] [YTS.MX]", m.Name)
+	require.Len(t, m.Trackers, 7)
+	require.Equal(t, "udp://tracker.opentrackr.org:1337/announce", m.Trackers[0])
+
+	m, err = debrid.ParseMagnet("magnet:?xt=urn:btih:kc35v63rg7f6z4cf66hi567evqnjbujz&xl=828818888")
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadHash, m.InfoHash)
+	require.Equal(t, int64(828818888), m.Length)
+
+	_, err = debrid.ParseMagnet("magnet:?dn=foo")
+	require.ErrorIs(t, err, debrid.ErrorInvalidMagnet)
+	_, err = debrid.ParseMagnet("https://example.com/?xt=urn:btih:" + nightOfTheLivingDeadHash)
+	require.ErrorIs(t, err, debrid.ErrorInvalidMagnet)
+
+	hash, err := debrid.ParseInfoHash(nightOfTheLivingDeadMagnet)
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadHash, hash)
+}
+
+func TestBuildMagnet(t *testing.T) {
+	magnet, err := debrid.BuildMagnet("50b7dafb7137cbecf045f78e8efbe4ac1a90d139", "Night of the Living Dead (1968) [720p] [YTS.MX]", "udp://tracker.opentrackr.org:1337/announce")
+	require.NoError(t, err)
+	require.Equal(t, "magnet:?xt=urn:btih:50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139&dn=Night+of+the+Living+Dead+%281968%29+%5B720p%5D+%5BYTS.MX%5D&tr=udp%3A%2F%2Ftracker.opentrackr.org%3A1337%2Fannounce", magnet)
+
+	// Round trip
+	m, err := debrid.ParseMagnet(nightOfTheLivingDeadMagnet)
+	require.NoError(t, err)
+	require.Equal(t, nightOfTheLivingDeadMagnet, m.String())
+}
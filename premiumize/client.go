@@ -12,6 +12,8 @@ import (
 
 	"github.com/tidwall/gjson"
 	"go.uber.org/zap"
+
+	debrid "github.com/deflix-tv/go-debrid"
 )
 
 var zapDebridService = zap.String("debridService", "Premiumize")
@@ -42,10 +44,19 @@ type Auth struct {
 	KeyOrToken string
 	// Flag for indicating whether KeyOrToken is a key (false) or token (true).
 	OAuth2 bool
+	// Point in time when the OAuth2 access token expires. Zero if unknown or for API keys.
+	// The client doesn't send requests with an expired token, but returns ErrorTokenExpired, so the user must re-authorize.
+	Expiry time.Time
 	// The user's original IP. Only required if ClientOptions.ForwardOriginIP is true.
 	IP string
 }
 
+// Expired reports whether the OAuth2 access token is expired.
+// It's always false for API keys and tokens with unknown expiry.
+func (a Auth) Expired() bool {
+	return a.OAuth2 && !a.Expiry.IsZero() && time.Now().After(a.Expiry)
+}
+
 // Client represents a Premiumize client.
 type Client struct {
 	opts       ClientOptions
@@ -82,10 +93,19 @@ func NewClient(opts ClientOptions, auth Auth, logger *zap.Logger) *Client {
 // The source can be an HTTP(S) link to a supported container file, website or magnet link.
 // Transfers that are created this way will appear in the transfer list.
 func (c *Client) CreateTransfer(ctx context.Context, source string) (CreatedTransfer, error) {
+	return c.CreateTransferInFolder(ctx, source, "")
+}
+
+// CreateTransferInFolder creates a transfer like CreateTransfer, but the transfer's files are put into the given folder of the user's cloud storage.
+// An empty folder ID puts them into the root folder.
+func (c *Client) CreateTransferInFolder(ctx context.Context, source, folderID string) (CreatedTransfer, error) {
 	c.logger.Debug("Creating transfer...", zapDebridService)
 
 	data := url.Values{}
 	data.Set("src", source)
+	if folderID != "" {
+		data.Set("folder_id", folderID)
+	}
 	resBytes, err := c.post(ctx, c.opts.BaseURL+"/transfer/create", data, true)
 	if err != nil {
 		return CreatedTransfer{}, fmt.Errorf("couldn't create transfer: %w", err)
@@ -103,6 +123,39 @@ func (c *Client) CreateTransfer(ctx context.Context, source string) (CreatedTran
 	return tf, nil
 }
 
+// CreateTransferFromTorrent creates a transfer via the content of a .torrent file.
+// The file name is only used as name of the uploaded file.
+// Transfers that are created this way will appear in the transfer list.
+func (c *Client) CreateTransferFromTorrent(ctx context.Context, fileName string, torrent []byte) (CreatedTransfer, error) {
+	return c.CreateTransferFromTorrentInFolder(ctx, fileName, torrent, "")
+}
+
+// CreateTransferFromTorrentInFolder creates a transfer like CreateTransferFromTorrent, but the transfer's files are put into the given folder of the user's cloud storage.
+// An empty folder ID puts them into the root folder.
+func (c *Client) CreateTransferFromTorrentInFolder(ctx context.Context, fileName string, torrent []byte, folderID string) (CreatedTransfer, error) {
+	c.logger.Debug("Creating transfer from torrent...", zapDebridService)
+
+	data := url.Values{}
+	if folderID != "" {
+		data.Set("folder_id", folderID)
+	}
+	resBytes, err := c.postFile(ctx, c.opts.BaseURL+"/transfer/create", data, "file", fileName, torrent)
+	if err != nil {
+		return CreatedTransfer{}, fmt.Errorf("couldn't create transfer from torrent: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return CreatedTransfer{}, fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	tf := CreatedTransfer{}
+	if err = json.Unmarshal(resBytes, &tf); err != nil {
+		return CreatedTransfer{}, fmt.Errorf("couldn't unmarshal added transfer: %w", err)
+	}
+
+	c.logger.Debug("Created transfer from torrent", zap.String("createdTransfer", fmt.Sprintf("%+v", tf)), zapDebridService)
+	return tf, nil
+}
+
 // CreateDDL creates direct download links.
 // The source can be an HTTP(S) link to a supported container file, website or magnet link.
 // The creation will only work if the file is cached on Premiumize or if a transfer for the file has been created before and the transfer finished downloading (to Premiumize).
@@ -179,6 +232,19 @@ func (c *Client) DeleteTransfer(ctx context.Context, id string) error {
 	return nil
 }
 
+// ClearFinishedTransfers removes all finished transfers from the user's transfers.
+// The files of the transfers stay in the user's cloud storage.
+func (c *Client) ClearFinishedTransfers(ctx context.Context) error {
+	c.logger.Debug("Clearing finished transfers...", zapDebridService)
+
+	if err := c.postSuccess(ctx, c.opts.BaseURL+"/transfer/clearfinished", nil); err != nil {
+		return fmt.Errorf("couldn't clear finished transfers: %w", err)
+	}
+
+	c.logger.Debug("Cleared finished transfers", zapDebridService)
+	return nil
+}
+
 // GetAccountInfo fetches and returns info about the user's account.
 func (c *Client) GetAccountInfo(ctx context.Context) (AccountInfo, error) {
 	c.logger.Debug("Getting account info...", zapDebridService)
@@ -202,7 +268,8 @@ func (c *Client) GetAccountInfo(ctx context.Context) (AccountInfo, error) {
 
 // CheckCache checks if files are already in Premiumize's cache.
 // An item can be any link that Premiumize supports: Containers, direct links, magnet URLs, torrent info hashes.
-// The returned map contains only entries for cached files and uses the item as key.
+// The returned map contains only entries for cached files.
+// For magnet URLs and torrent info hashes the key is the normalized info hash (see debrid.NormalizeInfoHash), for other items the key is the item itself.
 func (c *Client) CheckCache(ctx context.Context, items ...string) (map[string]CachedFile, error) {
 	c.logger.Debug("Checking cache...", zapDebridService)
 
@@ -218,10 +285,14 @@ func (c *Client) CheckCache(ctx context.Context, items ...string) (map[string]Ca
 	cachedFiles := make(map[string]CachedFile, len(items))
 	for i, item := range items {
 		if gjson.GetBytes(resBytes, "response."+strconv.Itoa(i)).Bool() {
+			if hash, err := debrid.ParseInfoHash(item); err == nil {
+				item = hash
+			}
 			cachedFiles[item] = CachedFile{
 				Transcoded: gjson.GetBytes(resBytes, "transcoded."+strconv.Itoa(i)).Bool(),
 				Filename:   gjson.GetBytes(resBytes, "filename."+strconv.Itoa(i)).String(),
-				Filesize:   gjson.GetBytes(resBytes, "filesize."+strconv.Itoa(i)).String(),
+				// gjson parses the size regardless of whether it's sent as string or number
+				Filesize: gjson.GetBytes(resBytes, "filesize."+strconv.Itoa(i)).Int(),
 			}
 		}
 	}
@@ -64,7 +64,7 @@ func TestClient(t *testing.T) {
 			break
 		}
 	}
-	require.Equal(t, "finished", transfer.Status)
+	require.Equal(t, premiumize.TransferStatusFinished, transfer.Status)
 
 	// Create direct download link
 	downloads, err := client.CreateDDL(ctx, transfer.Src)
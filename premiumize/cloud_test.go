@@ -0,0 +1,160 @@
+package premiumize_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolders(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/folder/list": {
+			Body: `{"status":"success","content":[{"id":"f1","name":"movie.mkv","type":"file","size":1234,"created_at":1600000000,"link":"https://example.com/movie.mkv"},{"id":"d1","name":"Sub","type":"folder"}],"name":"Movies","parent_id":"root","folder_id":"d0","breadcrumbs":[{"id":"root","name":"root","parent_id":""}]}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "d0", r.URL.Query().Get("id"))
+				assert.Equal(t, "true", r.URL.Query().Get("includebreadcrumbs"))
+			},
+		},
+		"/folder/create": {
+			Body: `{"status":"success","id":"d2"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "New", r.PostForm.Get("name"))
+				assert.Equal(t, "d0", r.PostForm.Get("parent_id"))
+			},
+		},
+		"/folder/paste": {
+			Body: `{"status":"success"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "d2", r.PostForm.Get("id"))
+				assert.Equal(t, []string{"f1"}, r.PostForm["files[]"])
+				assert.Equal(t, []string{"d1"}, r.PostForm["folders[]"])
+			},
+		},
+		"/folder/rename": {
+			Body: `{"status":"success"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "d2", r.PostForm.Get("id"))
+				assert.Equal(t, "Renamed", r.PostForm.Get("name"))
+			},
+		},
+		"/folder/delete": {
+			Body: `{"status":"error","message":"Folder not found"}`,
+		},
+	})
+	ctx := context.Background()
+
+	folder, err := client.ListFolder(ctx, "d0", true)
+	require.NoError(t, err)
+	require.Equal(t, "Movies", folder.Name)
+	require.Len(t, folder.Content, 2)
+	require.Equal(t, int64(1234), folder.Content[0].Size)
+	require.True(t, folder.Content[0].CreatedAt.Equal(time.Unix(1600000000, 0)))
+	require.True(t, folder.Content[1].CreatedAt.IsZero())
+	require.Equal(t, "folder", folder.Content[1].Type)
+	require.Len(t, folder.Breadcrumbs, 1)
+
+	id, err := client.CreateFolder(ctx, "New", "d0")
+	require.NoError(t, err)
+	require.Equal(t, "d2", id)
+
+	err = client.PasteIntoFolder(ctx, id, []string{"f1"}, []string{"d1"})
+	require.NoError(t, err)
+
+	err = client.RenameFolder(ctx, id, "Renamed")
+	require.NoError(t, err)
+
+	err = client.DeleteFolder(ctx, "foo")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Folder not found")
+}
+
+func TestItems(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/item/listall": {
+			Body: `{"status":"success","files":[{"id":"f1","name":"movie.mkv","size":"5000000000","mime_type":"video/x-matroska","created_at":1600000000,"path":"Movies/movie.mkv"}]}`,
+		},
+		"/item/details": {
+			Body: `{"id":"f1","name":"movie.mkv","type":"file","size":1234,"folder_id":"d0","resx":"1920","resy":1080,"duration":"5400.5","bitrate":1.5,"transcode_status":"finished","stream_link":"https://example.com/movie.mp4"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "f1", r.URL.Query().Get("id"))
+			},
+		},
+		"/item/delete": {
+			Body: `{"status":"success"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "f1", r.PostForm.Get("id"))
+			},
+		},
+		"/item/rename": {
+			Body: `{"status":"success"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "f1", r.PostForm.Get("id"))
+				assert.Equal(t, "film.mkv", r.PostForm.Get("name"))
+			},
+		},
+	})
+	ctx := context.Background()
+
+	items, err := client.ListAllItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "Movies/movie.mkv", items[0].Path)
+	require.Equal(t, int64(5000000000), items[0].Size)
+
+	details, err := client.GetItemDetails(ctx, "f1")
+	require.NoError(t, err)
+	require.Equal(t, "d0", details.FolderID)
+	require.Equal(t, 1920, details.ResX)
+	require.Equal(t, 1080, details.ResY)
+	require.Equal(t, 5400500*time.Millisecond, details.Duration)
+	require.Equal(t, 1.5, details.Bitrate)
+
+	err = client.RenameItem(ctx, "f1", "film.mkv")
+	require.NoError(t, err)
+
+	err = client.DeleteItem(ctx, "f1")
+	require.NoError(t, err)
+}
+
+func TestGenerateZip(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/zip/generate": {
+			Body: `{"status":"success","location":"https://example.com/pack.zip"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, []string{"f1", "f2"}, r.PostForm["files[]"])
+				assert.Equal(t, []string{"d1"}, r.PostForm["folders[]"])
+			},
+		},
+	})
+
+	location, err := client.GenerateZip(context.Background(), []string{"f1", "f2"}, []string{"d1"})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/pack.zip", location)
+}
+
+func TestListServices(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/services/list": {
+			Body: `{"directdl":["rapidgator.net","uploaded.net"],"cache":["rapidgator.net"],"fairusefactor":{"rapidgator.net":1,"uploaded.net":2.5},"aliases":{"uploaded.net":["ul.to"]},"regexpatterns":{"rapidgator.net":["rapidgator\\.net/file/.+"]}}`,
+		},
+	})
+
+	services, err := client.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, services.DirectDL, 2)
+	require.Equal(t, 2.5, services.FairUseFactor["uploaded.net"])
+	require.True(t, services.SupportsHost("ul.to"))
+	require.False(t, services.CachesHost("ul.to"))
+	require.True(t, services.CachesHost("rapidgator.net"))
+	require.False(t, services.SupportsHost("example.com"))
+}
@@ -0,0 +1,25 @@
+package premiumize_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/deflix-tv/go-debrid/internal/fakeserver"
+	"github.com/deflix-tv/go-debrid/premiumize"
+)
+
+// fakeResponse is a canned response of the fake Premiumize server.
+type fakeResponse = fakeserver.Response
+
+// newFakeClient starts a fake Premiumize server that responds to requests to paths like "/folder/list" with the given responses,
+// and returns a client that sends its requests to it.
+func newFakeClient(t *testing.T, responses map[string]fakeResponse) *premiumize.Client {
+	server := fakeserver.New(t, fakeserver.ByPath, func(t *testing.T, r *http.Request) {
+		assert.Equal(t, "123", r.URL.Query().Get("apikey"))
+	}, responses)
+	opts := premiumize.DefaultClientOpts
+	opts.BaseURL = server.URL
+	return premiumize.NewClient(opts, premiumize.Auth{KeyOrToken: "123"}, nil)
+}
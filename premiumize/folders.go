@@ -0,0 +1,110 @@
+package premiumize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// ListFolder fetches and returns a folder of the user's cloud storage, including its content.
+// An empty ID lists the root folder. When includeBreadcrumbs is true, the folders from the root folder to the folder are included.
+func (c *Client) ListFolder(ctx context.Context, id string, includeBreadcrumbs bool) (Folder, error) {
+	c.logger.Debug("Listing folder...", zapDebridService)
+
+	data := url.Values{}
+	if id != "" {
+		data.Set("id", id)
+	}
+	if includeBreadcrumbs {
+		data.Set("includebreadcrumbs", "true")
+	}
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/folder/list", data)
+	if err != nil {
+		return Folder{}, fmt.Errorf("couldn't list folder: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return Folder{}, fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	folder := Folder{}
+	if err = json.Unmarshal(resBytes, &folder); err != nil {
+		return Folder{}, fmt.Errorf("couldn't unmarshal folder: %w", err)
+	}
+
+	c.logger.Debug("Listed folder", zap.String("folder", fmt.Sprintf("%+v", folder)), zapDebridService)
+	return folder, nil
+}
+
+// CreateFolder creates a folder in the user's cloud storage and returns its ID.
+// An empty parent ID creates the folder in the root folder.
+func (c *Client) CreateFolder(ctx context.Context, name, parentID string) (string, error) {
+	c.logger.Debug("Creating folder...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("name", name)
+	if parentID != "" {
+		data.Set("parent_id", parentID)
+	}
+	resBytes, err := c.post(ctx, c.opts.BaseURL+"/folder/create", data, true)
+	if err != nil {
+		return "", fmt.Errorf("couldn't create folder: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return "", fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	id := gjson.GetBytes(resBytes, "id").String()
+
+	c.logger.Debug("Created folder", zap.String("id", id), zapDebridService)
+	return id, nil
+}
+
+// RenameFolder renames a folder in the user's cloud storage.
+func (c *Client) RenameFolder(ctx context.Context, id, name string) error {
+	c.logger.Debug("Renaming folder...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("id", id)
+	data.Set("name", name)
+	if err := c.postSuccess(ctx, c.opts.BaseURL+"/folder/rename", data); err != nil {
+		return fmt.Errorf("couldn't rename folder: %w", err)
+	}
+
+	c.logger.Debug("Renamed folder", zapDebridService)
+	return nil
+}
+
+// PasteIntoFolder moves files and folders into a folder of the user's cloud storage.
+// An empty ID moves them into the root folder.
+func (c *Client) PasteIntoFolder(ctx context.Context, id string, fileIDs, folderIDs []string) error {
+	c.logger.Debug("Pasting into folder...", zapDebridService)
+
+	data := url.Values{"files[]": fileIDs, "folders[]": folderIDs}
+	if id != "" {
+		data.Set("id", id)
+	}
+	if err := c.postSuccess(ctx, c.opts.BaseURL+"/folder/paste", data); err != nil {
+		return fmt.Errorf("couldn't paste into folder: %w", err)
+	}
+
+	c.logger.Debug("Pasted into folder", zapDebridService)
+	return nil
+}
+
+// DeleteFolder deletes a folder from the user's cloud storage, including its content.
+func (c *Client) DeleteFolder(ctx context.Context, id string) error {
+	c.logger.Debug("Deleting folder...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("id", id)
+	if err := c.postSuccess(ctx, c.opts.BaseURL+"/folder/delete", data); err != nil {
+		return fmt.Errorf("couldn't delete folder: %w", err)
+	}
+
+	c.logger.Debug("Deleted folder", zapDebridService)
+	return nil
+}
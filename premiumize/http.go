@@ -1,22 +1,37 @@
 package premiumize
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/tidwall/gjson"
 	"go.uber.org/zap"
 )
 
+// authQuery returns the query string for authenticating requests, including the leading "?".
+// It returns ErrorTokenExpired if the OAuth2 access token is known to be expired.
+func (c *Client) authQuery() (string, error) {
+	if !c.auth.OAuth2 {
+		return "?apikey=" + c.auth.KeyOrToken, nil
+	}
+	if c.auth.Expired() {
+		return "", ErrorTokenExpired
+	}
+	return "?access_token=" + c.auth.KeyOrToken, nil
+}
+
 func (c *Client) get(ctx context.Context, urlString string, data url.Values) ([]byte, error) {
-	if c.auth.OAuth2 {
-		urlString += "?access_token=" + c.auth.KeyOrToken
-	} else {
-		urlString += "?apikey=" + c.auth.KeyOrToken
+	authQuery, err := c.authQuery()
+	if err != nil {
+		return nil, err
 	}
+	urlString += authQuery
 
 	// map[string][]string
 	for k, vals := range data {
@@ -55,14 +70,13 @@ func (c *Client) get(ctx context.Context, urlString string, data url.Values) ([]
 }
 
 func (c *Client) post(ctx context.Context, urlString string, data url.Values, form bool) ([]byte, error) {
-	if c.auth.OAuth2 {
-		urlString += "?access_token=" + c.auth.KeyOrToken
-	} else {
-		urlString += "?apikey=" + c.auth.KeyOrToken
+	authQuery, err := c.authQuery()
+	if err != nil {
+		return nil, err
 	}
+	urlString += authQuery
 
 	var req *http.Request
-	var err error
 	if form {
 		req, err = http.NewRequest("POST", urlString, strings.NewReader(data.Encode()))
 	} else {
@@ -104,3 +118,76 @@ func (c *Client) post(ctx context.Context, urlString string, data url.Values, fo
 	}
 	return resBody, nil
 }
+
+// postSuccess sends a form POST request to an endpoint that only responds with a status, and checks the status.
+func (c *Client) postSuccess(ctx context.Context, urlString string, data url.Values) error {
+	resBytes, err := c.post(ctx, urlString, data, true)
+	if err != nil {
+		return err
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	return nil
+}
+
+// postFile sends a multipart/form-data POST request with a single file and optional additional form fields.
+// data can be nil.
+func (c *Client) postFile(ctx context.Context, urlString string, data url.Values, fieldName, fileName string, content []byte) ([]byte, error) {
+	authQuery, err := c.authQuery()
+	if err != nil {
+		return nil, err
+	}
+	urlString += authQuery
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	// map[string][]string
+	for k, vals := range data {
+		for _, val := range vals {
+			if err := mw.WriteField(k, val); err != nil {
+				return nil, fmt.Errorf("couldn't write multipart form field: %w", err)
+			}
+		}
+	}
+	fw, err := mw.CreateFormFile(fieldName, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create multipart form file: %w", err)
+	}
+	if _, err = fw.Write(content); err != nil {
+		return nil, fmt.Errorf("couldn't write multipart form file: %w", err)
+	}
+	if err = mw.Close(); err != nil {
+		return nil, fmt.Errorf("couldn't close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", urlString, body)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create POST request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	for headerKey, headerVal := range c.opts.ExtraHeaders {
+		req.Header.Add(headerKey, headerVal)
+	}
+
+	c.logger.Debug("Sending request", zap.String("request", fmt.Sprintf("%+v", req)), zapDebridService)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't send POST request: %w", err)
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	c.logger.Debug("Got response", zap.Int("status", res.StatusCode), zap.NamedError("bodyReadError", err), zap.ByteString("response", resBody), zapDebridService)
+
+	// Check server response.
+	if res.StatusCode != http.StatusOK {
+		// resBody can be nil if above ioutil.ReadAll failed, but in that case we don't care about the related error.
+		return resBody, fmt.Errorf("bad HTTP response status: %v", res.Status)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read response body: %w", err)
+	}
+	return resBody, nil
+}
@@ -0,0 +1,106 @@
+package premiumize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// ListAllItems fetches and returns all files in the user's cloud storage, with their full path.
+func (c *Client) ListAllItems(ctx context.Context) ([]Item, error) {
+	c.logger.Debug("Listing all items...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/item/listall", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list all items: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return nil, fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	items := []Item{}
+	if filesJSON := gjson.GetBytes(resBytes, "files"); filesJSON.Exists() {
+		if err = json.Unmarshal([]byte(filesJSON.Raw), &items); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal items: %w", err)
+		}
+	}
+
+	c.logger.Debug("Listed all items", zap.Int("itemCount", len(items)), zapDebridService)
+	return items, nil
+}
+
+// GetItemDetails fetches and returns details about a file in the user's cloud storage.
+func (c *Client) GetItemDetails(ctx context.Context, id string) (ItemDetails, error) {
+	c.logger.Debug("Getting item details...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("id", id)
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/item/details", data)
+	if err != nil {
+		return ItemDetails{}, fmt.Errorf("couldn't get item details: %w", err)
+	}
+	// This endpoint only sends a status in case of an error
+	if status := gjson.GetBytes(resBytes, "status"); status.Exists() && status.String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return ItemDetails{}, fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	details := ItemDetails{}
+	if err = json.Unmarshal(resBytes, &details); err != nil {
+		return ItemDetails{}, fmt.Errorf("couldn't unmarshal item details: %w", err)
+	}
+
+	c.logger.Debug("Got item details", zap.String("details", fmt.Sprintf("%+v", details)), zapDebridService)
+	return details, nil
+}
+
+// RenameItem renames a file in the user's cloud storage.
+func (c *Client) RenameItem(ctx context.Context, id, name string) error {
+	c.logger.Debug("Renaming item...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("id", id)
+	data.Set("name", name)
+	if err := c.postSuccess(ctx, c.opts.BaseURL+"/item/rename", data); err != nil {
+		return fmt.Errorf("couldn't rename item: %w", err)
+	}
+
+	c.logger.Debug("Renamed item", zapDebridService)
+	return nil
+}
+
+// DeleteItem deletes a file from the user's cloud storage.
+func (c *Client) DeleteItem(ctx context.Context, id string) error {
+	c.logger.Debug("Deleting item...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("id", id)
+	if err := c.postSuccess(ctx, c.opts.BaseURL+"/item/delete", data); err != nil {
+		return fmt.Errorf("couldn't delete item: %w", err)
+	}
+
+	c.logger.Debug("Deleted item", zapDebridService)
+	return nil
+}
+
+// GenerateZip bundles files and folders of the user's cloud storage into a single zip file and returns its download link.
+func (c *Client) GenerateZip(ctx context.Context, fileIDs, folderIDs []string) (string, error) {
+	c.logger.Debug("Generating zip...", zapDebridService)
+
+	data := url.Values{"files[]": fileIDs, "folders[]": folderIDs}
+	resBytes, err := c.post(ctx, c.opts.BaseURL+"/zip/generate", data, true)
+	if err != nil {
+		return "", fmt.Errorf("couldn't generate zip: %w", err)
+	}
+	if gjson.GetBytes(resBytes, "status").String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return "", fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	location := gjson.GetBytes(resBytes, "location").String()
+
+	c.logger.Debug("Generated zip", zap.String("location", location), zapDebridService)
+	return location, nil
+}
@@ -21,6 +21,11 @@ type LegacyClientOptions struct {
 	Timeout      time.Duration
 	CacheAge     time.Duration
 	ExtraHeaders []string
+	// Selects the file to stream from a torrent with multiple files. Defaults to debrid.DefaultFileSelector.
+	FileSelector debrid.FileSelector
+	// Decides whether GetStreamURL returns the transcoded stream link of the selected file instead of its original link, for example BrowserStreamPolicy().
+	// The original link is returned when no transcoded stream is available. The zero value always returns the original link.
+	StreamPolicy StreamPolicy
 	// When setting this to true, the user's original IP address is read from Auth.IP and forwarded to Premiumize when creating a direct download links.
 	// Only required if the library is used in an app on a machine
 	// whose outgoing IP is different from the machine that's going to request the cached file/stream URL.
@@ -42,6 +47,8 @@ type LegacyClient struct {
 	availabilityCache debrid.Cache
 	cacheAge          time.Duration
 	extraHeaders      map[string]string
+	fileSelector      debrid.FileSelector
+	streamPolicy      StreamPolicy
 	forwardOriginIP   bool
 	logger            *zap.Logger
 }
@@ -68,6 +75,10 @@ func NewLegacyClient(opts LegacyClientOptions, apiKeyCache, availabilityCache de
 		}
 	}
 
+	if opts.FileSelector == nil {
+		opts.FileSelector = debrid.DefaultFileSelector
+	}
+
 	return &LegacyClient{
 		baseURL: opts.BaseURL,
 		httpClient: &http.Client{
@@ -77,6 +88,8 @@ func NewLegacyClient(opts LegacyClientOptions, apiKeyCache, availabilityCache de
 		availabilityCache: availabilityCache,
 		cacheAge:          opts.CacheAge,
 		extraHeaders:      extraHeaderMap,
+		fileSelector:      opts.FileSelector,
+		streamPolicy:      opts.StreamPolicy,
 		forwardOriginIP:   opts.ForwardOriginIP,
 		logger:            logger,
 	}, nil
@@ -140,6 +153,12 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, auth Auth,
 	var unknownAvailailabilityValues []string
 	for _, infoHash := range infoHashes {
 		zapFieldInfoHash := zap.String("infoHash", infoHash)
+		// Normalize so that cache keys and results are consistent, no matter how the info_hash was passed
+		infoHash, err := debrid.NormalizeInfoHash(infoHash)
+		if err != nil {
+			c.logger.Error("Couldn't normalize info_hash", zap.Error(err), zapFieldInfoHash, zapFieldDebridSite, zapFieldAPItoken)
+			continue
+		}
 		created, found, err := c.availabilityCache.Get(infoHash)
 		if err != nil {
 			c.logger.Error("Couldn't decode availability cache item", zap.Error(err), zapFieldInfoHash, zapFieldDebridSite, zapFieldAPItoken)
@@ -203,8 +222,8 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, auth Auth,
 			if !isAvailable {
 				continue
 			}
+			// Already normalized
 			infoHash := unknownAvailailabilityValues[i]
-			infoHash = strings.ToUpper(infoHash)
 			result = append(result, infoHash)
 			// Create cache item
 			if err = c.availabilityCache.Set(infoHash); err != nil {
@@ -216,6 +235,12 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, auth Auth,
 }
 
 func (c *LegacyClient) GetStreamURL(ctx context.Context, magnetURL string, auth Auth) (string, error) {
+	return c.GetStreamURLWithSelector(ctx, magnetURL, auth, c.fileSelector)
+}
+
+// GetStreamURLWithSelector is like GetStreamURL, but uses the given file selector instead of the one from the client options.
+// This is useful for selecting a specific episode from a season pack, see debrid.EpisodeSelector.
+func (c *LegacyClient) GetStreamURLWithSelector(ctx context.Context, magnetURL string, auth Auth, selector debrid.FileSelector) (string, error) {
 	zapFieldDebridSite := zap.String("debridSite", "Premiumize")
 	zapFieldAPIkey := zap.String("keyOrToken", auth.KeyOrToken)
 	c.logger.Debug("Adding magnet to Premiumize...", zapFieldDebridSite, zapFieldAPIkey)
@@ -238,7 +263,7 @@ func (c *LegacyClient) GetStreamURL(ctx context.Context, magnetURL string, auth
 	}
 	c.logger.Debug("Finished adding magnet to Premiumize", zapFieldDebridSite, zapFieldAPIkey)
 	content := gjson.GetBytes(resBytes, "content").Array()
-	ddlLink, err := selectLink(ctx, content)
+	ddlLink, err := selectLink(ctx, content, selector, c.streamPolicy)
 	if err != nil {
 		return "", fmt.Errorf("Couldn't find proper link in magnet status: %v", err)
 	} else if ddlLink == "" {
@@ -330,21 +355,28 @@ func (c *LegacyClient) post(ctx context.Context, urlString string, auth Auth, da
 	return ioutil.ReadAll(res.Body)
 }
 
-func selectLink(ctx context.Context, linkResults []gjson.Result) (string, error) {
+func selectLink(ctx context.Context, linkResults []gjson.Result, selector debrid.FileSelector, policy StreamPolicy) (string, error) {
 	// Precondition check
 	if len(linkResults) == 0 {
 		return "", fmt.Errorf("Empty slice of content")
 	}
 
-	var link string
-	var size int64
-	for _, res := range linkResults {
-		if res.Get("size").Int() > size {
-			size = res.Get("size").Int()
-			link = res.Get("link").String()
-		}
+	files := make([]debrid.File, len(linkResults))
+	for i, res := range linkResults {
+		files[i] = debrid.File{Path: res.Get("path").String(), Size: res.Get("size").Int()}
+	}
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return "", err
 	}
 
+	dl := Download{
+		Path:            linkResults[i].Get("path").String(),
+		Link:            linkResults[i].Get("link").String(),
+		StreamLink:      linkResults[i].Get("stream_link").String(),
+		TranscodeStatus: TranscodeStatus(linkResults[i].Get("transcode_status").String()),
+	}
+	link := SelectStreamLink(dl, policy)
 	if link == "" {
 		return "", fmt.Errorf("No link found")
 	}
@@ -0,0 +1,199 @@
+package premiumize
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrorAuthorizationPending is returned by OAuth2Client.GetToken when the user hasn't entered the user code yet.
+	ErrorAuthorizationPending = errors.New("authorization pending")
+	// ErrorSlowDown is returned by OAuth2Client.GetToken when polling too fast.
+	ErrorSlowDown = errors.New("slow down")
+	// ErrorAccessDenied is returned by OAuth2Client.GetToken when the user denied the authorization.
+	ErrorAccessDenied = errors.New("access denied")
+	// ErrorDeviceCodeExpired is returned when the user didn't enter the user code before the device code expired.
+	ErrorDeviceCodeExpired = errors.New("device code expired")
+	// ErrorTokenExpired is returned by the Client when Auth.Expiry has passed. The user must then authorize again.
+	ErrorTokenExpired = errors.New("access token expired")
+)
+
+var oauth2ErrMap = map[string]error{
+	"authorization_pending": ErrorAuthorizationPending,
+	"slow_down":             ErrorSlowDown,
+	"access_denied":         ErrorAccessDenied,
+	"expired_token":         ErrorDeviceCodeExpired,
+}
+
+// OAuth2Options are options for the OAuth2 client.
+type OAuth2Options struct {
+	// Base URL for HTTP requests
+	BaseURL string
+	// Timeout for HTTP requests
+	Timeout time.Duration
+	// Client ID of the app, as registered with Premiumize. Required.
+	ClientID string
+}
+
+// DefaultOAuth2Opts are OAuth2Options with reasonable default values.
+// There's no default client ID, every app must register its own.
+var DefaultOAuth2Opts = OAuth2Options{
+	BaseURL: "https://www.premiumize.me",
+	Timeout: 5 * time.Second,
+}
+
+// OAuth2Client is a client for Premiumize's OAuth2 device authorization flow.
+// The flow is:
+//  1. GetDeviceCode and show DeviceCode.UserCode and DeviceCode.VerificationURI to the user
+//  2. WaitForAuth while the user enters the user code on the verification URI
+//  3. Use the returned Auth for a Client, and authorize again when Auth.Expired reports true
+type OAuth2Client struct {
+	opts       OAuth2Options
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewOAuth2Client returns a new Premiumize OAuth2 client.
+// The logger param can be nil.
+func NewOAuth2Client(opts OAuth2Options, logger *zap.Logger) *OAuth2Client {
+	// Set default values
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultOAuth2Opts.BaseURL
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultOAuth2Opts.Timeout
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &OAuth2Client{
+		opts: opts,
+		httpClient: &http.Client{
+			Timeout: opts.Timeout,
+		},
+		logger: logger,
+	}
+}
+
+// GetDeviceCode starts the device authorization flow and returns the codes for the user and for polling.
+func (c *OAuth2Client) GetDeviceCode(ctx context.Context) (DeviceCode, error) {
+	c.logger.Debug("Getting device code...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("client_id", c.opts.ClientID)
+	data.Set("response_type", "device_code")
+	resBytes, err := c.post(ctx, data)
+	if err != nil {
+		return DeviceCode{}, fmt.Errorf("couldn't get device code: %w", err)
+	}
+	code := DeviceCode{}
+	if err = json.Unmarshal(resBytes, &code); err != nil {
+		return DeviceCode{}, fmt.Errorf("couldn't unmarshal device code: %w", err)
+	}
+	code.Expiry = time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	c.logger.Debug("Got device code", zap.String("userCode", code.UserCode), zapDebridService)
+	return code, nil
+}
+
+// GetToken returns the token once the user entered the user code.
+// Until then ErrorAuthorizationPending is returned. Other possible errors are ErrorSlowDown, ErrorAccessDenied and ErrorDeviceCodeExpired.
+func (c *OAuth2Client) GetToken(ctx context.Context, deviceCode string) (Token, error) {
+	c.logger.Debug("Getting token...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("client_id", c.opts.ClientID)
+	data.Set("code", deviceCode)
+	data.Set("grant_type", "device_code")
+	resBytes, err := c.post(ctx, data)
+	if err != nil {
+		return Token{}, fmt.Errorf("couldn't get token: %w", err)
+	}
+	token := Token{}
+	if err = json.Unmarshal(resBytes, &token); err != nil {
+		return Token{}, fmt.Errorf("couldn't unmarshal token: %w", err)
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	c.logger.Debug("Got token", zap.Time("expiry", token.Expiry), zapDebridService)
+	return token, nil
+}
+
+// WaitForAuth polls Premiumize in the interval of the device code until the user entered the user code,
+// and then returns the Auth for a Client, with OAuth2 set and the token's expiry.
+// The interval is increased when Premiumize asks to slow down.
+// It returns ErrorDeviceCodeExpired when the device code expires, ErrorAccessDenied when the user denied the authorization,
+// or the context's error when the context is done.
+func (c *OAuth2Client) WaitForAuth(ctx context.Context, code DeviceCode) (Auth, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	for {
+		token, err := c.GetToken(ctx, code.DeviceCode)
+		if err == nil {
+			return token.Auth(), nil
+		} else if errors.Is(err, ErrorSlowDown) {
+			interval += 5 * time.Second
+		} else if !errors.Is(err, ErrorAuthorizationPending) {
+			return Auth{}, err
+		}
+		if !code.Expiry.IsZero() && time.Now().After(code.Expiry) {
+			return Auth{}, ErrorDeviceCodeExpired
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Auth{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *OAuth2Client) post(ctx context.Context, data url.Values) ([]byte, error) {
+	req, err := http.NewRequest("POST", c.opts.BaseURL+"/token", strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create POST request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't send POST request: %w", err)
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	// Don't log the response body, it contains secrets
+	c.logger.Debug("Got response", zap.Int("status", res.StatusCode), zap.NamedError("bodyReadError", err), zapDebridService)
+
+	// Check server response.
+	// OAuth2 errors like "authorization_pending" are sent with a 400 status code.
+	if res.StatusCode != http.StatusOK {
+		if err, found := oauth2ErrMap[gjson.GetBytes(resBody, "error").String()]; found {
+			return nil, err
+		}
+		// resBody can be nil if above ioutil.ReadAll failed, but in that case we don't care about the related error.
+		return resBody, fmt.Errorf("bad HTTP response status: %v", res.Status)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read response body: %w", err)
+	}
+	return resBody, nil
+}
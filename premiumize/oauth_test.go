@@ -0,0 +1,116 @@
+package premiumize_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/premiumize"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "CLIENT", r.PostForm.Get("client_id"))
+			if r.PostForm.Get("response_type") == "device_code" {
+				_, _ = w.Write([]byte(`{"device_code":"DEVICE","user_code":"USER","verification_uri":"https://www.premiumize.me/device","expires_in":600,"interval":1}`))
+				return
+			}
+			assert.Equal(t, "device_code", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "DEVICE", r.PostForm.Get("code"))
+			// The user enters the code after the first poll
+			if atomic.AddInt32(&polls, 1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"ACCESS","token_type":"Bearer","expires_in":3600}`))
+		case "/api/account/info":
+			assert.Equal(t, "ACCESS", r.URL.Query().Get("access_token"))
+			_, _ = w.Write([]byte(`{"status":"success","customer_id":"123"}`))
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oauth2Opts := premiumize.DefaultOAuth2Opts
+	oauth2Opts.BaseURL = server.URL
+	oauth2Opts.ClientID = "CLIENT"
+	oauth2Client := premiumize.NewOAuth2Client(oauth2Opts, nil)
+
+	code, err := oauth2Client.GetDeviceCode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "USER", code.UserCode)
+
+	auth, err := oauth2Client.WaitForAuth(ctx, code)
+	require.NoError(t, err)
+	require.True(t, auth.OAuth2)
+	require.Equal(t, "ACCESS", auth.KeyOrToken)
+	require.WithinDuration(t, time.Now().Add(time.Hour), auth.Expiry, time.Minute)
+	require.False(t, auth.Expired())
+
+	clientOpts := premiumize.DefaultClientOpts
+	clientOpts.BaseURL = server.URL + "/api"
+	client := premiumize.NewClient(clientOpts, auth, nil)
+	info, err := client.GetAccountInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "123", info.CustomerID)
+
+	// Expired tokens aren't sent
+	auth.Expiry = time.Now().Add(-time.Second)
+	require.True(t, auth.Expired())
+	client = premiumize.NewClient(clientOpts, auth, nil)
+	_, err = client.GetAccountInfo(ctx)
+	require.ErrorIs(t, err, premiumize.ErrorTokenExpired)
+}
+
+func TestDeviceFlowErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		w.WriteHeader(http.StatusBadRequest)
+		switch r.PostForm.Get("code") {
+		case "DENIED":
+			_, _ = w.Write([]byte(`{"error":"access_denied"}`))
+		case "EXPIRED":
+			_, _ = w.Write([]byte(`{"error":"expired_token"}`))
+		default:
+			_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+		}
+	}))
+	defer server.Close()
+
+	oauth2Opts := premiumize.DefaultOAuth2Opts
+	oauth2Opts.BaseURL = server.URL
+	oauth2Client := premiumize.NewOAuth2Client(oauth2Opts, nil)
+	ctx := context.Background()
+
+	_, err := oauth2Client.WaitForAuth(ctx, premiumize.DeviceCode{DeviceCode: "DENIED"})
+	require.ErrorIs(t, err, premiumize.ErrorAccessDenied)
+
+	_, err = oauth2Client.WaitForAuth(ctx, premiumize.DeviceCode{DeviceCode: "EXPIRED"})
+	require.ErrorIs(t, err, premiumize.ErrorDeviceCodeExpired)
+
+	// Local expiry
+	_, err = oauth2Client.WaitForAuth(ctx, premiumize.DeviceCode{DeviceCode: "PENDING", Expiry: time.Now().Add(-time.Second)})
+	require.ErrorIs(t, err, premiumize.ErrorDeviceCodeExpired)
+
+	// Context cancellation
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = oauth2Client.WaitForAuth(ctx, premiumize.DeviceCode{DeviceCode: "PENDING", Expiry: time.Now().Add(time.Hour)})
+	require.Error(t, err)
+}
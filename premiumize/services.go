@@ -0,0 +1,56 @@
+package premiumize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// ListServices fetches and returns the hosters that Premiumize supports.
+func (c *Client) ListServices(ctx context.Context) (Services, error) {
+	c.logger.Debug("Listing services...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/services/list", nil)
+	if err != nil {
+		return Services{}, fmt.Errorf("couldn't list services: %w", err)
+	}
+	// This endpoint only sends a status in case of an error
+	if status := gjson.GetBytes(resBytes, "status"); status.Exists() && status.String() != "success" {
+		message := gjson.GetBytes(resBytes, "message").String()
+		return Services{}, fmt.Errorf("got error response from Premiumize: %v", message)
+	}
+	services := Services{}
+	if err = json.Unmarshal(resBytes, &services); err != nil {
+		return Services{}, fmt.Errorf("couldn't unmarshal services: %w", err)
+	}
+
+	c.logger.Debug("Listed services", zap.Int("directDLCount", len(services.DirectDL)), zap.Int("cacheCount", len(services.Cache)), zapDebridService)
+	return services, nil
+}
+
+// SupportsHost returns true if Premiumize supports direct downloads from the host, either by its name or one of its aliases.
+func (s Services) SupportsHost(host string) bool {
+	return s.hostIn(host, s.DirectDL)
+}
+
+// CachesHost returns true if Premiumize can cache downloads from the host, either by its name or one of its aliases.
+func (s Services) CachesHost(host string) bool {
+	return s.hostIn(host, s.Cache)
+}
+
+func (s Services) hostIn(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if h == host {
+			return true
+		}
+		for _, alias := range s.Aliases[h] {
+			if alias == host {
+				return true
+			}
+		}
+	}
+	return false
+}
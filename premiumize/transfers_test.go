@@ -0,0 +1,53 @@
+package premiumize_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/premiumize"
+)
+
+func TestTransfers(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"/transfer/create": {
+			Body: `{"status":"success","id":"t1","name":"movie","type":"torrent"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				// FormValue parses both url-encoded and multipart forms
+				assert.Equal(t, "d0", r.FormValue("folder_id"))
+			},
+		},
+		"/transfer/list": {
+			Body: `{"status":"success","transfers":[{"id":"t1","status":"seeding","progress":1},{"id":"t2","status":"running","progress":0.5},{"id":"t3","status":"timeout"}]}`,
+		},
+		"/transfer/clearfinished": {
+			Body: `{"status":"success"}`,
+			Check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+			},
+		},
+	})
+	ctx := context.Background()
+
+	tf, err := client.CreateTransferInFolder(ctx, "magnet:?xt=urn:btih:50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139", "d0")
+	require.NoError(t, err)
+	require.Equal(t, "t1", tf.ID)
+	tf, err = client.CreateTransferFromTorrentInFolder(ctx, "movie.torrent", []byte("d4:infoe"), "d0")
+	require.NoError(t, err)
+	require.Equal(t, "t1", tf.ID)
+
+	transfers, err := client.ListTransfers(ctx)
+	require.NoError(t, err)
+	require.Len(t, transfers, 3)
+	require.Equal(t, premiumize.TransferStatusSeeding, transfers[0].Status)
+	require.True(t, transfers[0].Status.Done())
+	require.False(t, transfers[1].Status.Done())
+	require.False(t, transfers[1].Status.Failed())
+	require.True(t, transfers[2].Status.Failed())
+
+	err = client.ClearFinishedTransfers(ctx)
+	require.NoError(t, err)
+}
@@ -1,5 +1,12 @@
 package premiumize
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/deflix-tv/go-debrid/internal/jsonutil"
+)
+
 // CreatedTransfer represents a transfer that has just been added to Premiumize.
 type CreatedTransfer struct {
 	Type string `json:"type,omitempty"`
@@ -9,21 +16,36 @@ type CreatedTransfer struct {
 
 // Download represents a direct download. If a transfer was created by adding a torrent, a Download is a file in that torrent.
 type Download struct {
-	Path            string `json:"path,omitempty"`
-	Size            string `json:"size,omitempty"`
-	Link            string `json:"link,omitempty"`
-	StreamLink      string `json:"stream_link,omitempty"`
-	TranscodeStatus string `json:"transcode_status,omitempty"`
+	Path string `json:"path,omitempty"`
+	// File size in bytes
+	Size            int64           `json:"size,omitempty"`
+	Link            string          `json:"link,omitempty"`
+	StreamLink      string          `json:"stream_link,omitempty"`
+	TranscodeStatus TranscodeStatus `json:"transcode_status,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Premiumize sends the size as string.
+func (d *Download) UnmarshalJSON(data []byte) error {
+	type download Download
+	raw := struct {
+		*download
+		Size jsonutil.Int `json:"size,omitempty"`
+	}{download: (*download)(d)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	d.Size = int64(raw.Size)
+	return nil
 }
 
 // Transfer represents a transfer, like a torrent that has been added to Premiumize for a specific user.
 type Transfer struct {
 	ID string `json:"id,omitempty"`
 	// Name of the torrent if the transfer was created by adding a torrent
-	Name    string `json:"name,omitempty"`
-	Message string `json:"message,omitempty"`
-	// "waiting", "finished" etc.
-	Status string `json:"status,omitempty"`
+	Name    string         `json:"name,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Status  TransferStatus `json:"status,omitempty"`
 	// Download progress. Can be 0 for cached files that don't have to be downloaded.
 	Progress float64 `json:"progress,omitempty"`
 	// When the transfer was created by adding a torrent via magnet URL, then this is the magnet URL
@@ -32,17 +54,280 @@ type Transfer struct {
 	FileID   string `json:"file_id,omitempty"`
 }
 
+// TransferStatus is the state of a transfer.
+type TransferStatus string
+
+// Transfer states
+const (
+	TransferStatusWaiting  TransferStatus = "waiting"
+	TransferStatusQueued   TransferStatus = "queued"
+	TransferStatusRunning  TransferStatus = "running"
+	TransferStatusSeeding  TransferStatus = "seeding"
+	TransferStatusFinished TransferStatus = "finished"
+	TransferStatusDeleted  TransferStatus = "deleted"
+	TransferStatusBanned   TransferStatus = "banned"
+	TransferStatusError    TransferStatus = "error"
+	TransferStatusTimeout  TransferStatus = "timeout"
+)
+
+// TranscodeStatus is the state of the transcoding of a file.
+// Premiumize transcodes video files to MP4, which is available via the stream link when the transcoding is finished.
+type TranscodeStatus string
+
+// Transcode states
+const (
+	TranscodeStatusNotApplicable TranscodeStatus = "not_applicable"
+	TranscodeStatusPending       TranscodeStatus = "pending"
+	TranscodeStatusRunning       TranscodeStatus = "running"
+	TranscodeStatusFinished      TranscodeStatus = "finished"
+)
+
+// Done returns true if the transfer's files are available in the user's cloud storage.
+// A seeding transfer has finished downloading.
+func (s TransferStatus) Done() bool {
+	return s == TransferStatusFinished || s == TransferStatusSeeding
+}
+
+// Failed returns true if the transfer won't finish.
+func (s TransferStatus) Failed() bool {
+	switch s {
+	case TransferStatusDeleted, TransferStatusBanned, TransferStatusError, TransferStatusTimeout:
+		return true
+	}
+	return false
+}
+
 // AccountInfo contains info about a user account.
 type AccountInfo struct {
-	CustomerID   string  `json:"customer_id,omitempty"`
-	PremiumUntil int     `json:"premium_until,omitempty"`
-	LimitUsed    float64 `json:"limit_used,omitempty"`
-	SpaceUsed    float64 `json:"space_used,omitempty"`
+	CustomerID string `json:"customer_id,omitempty"`
+	// Point in time until the user is premium. Zero if the user is not premium.
+	PremiumUntil time.Time `json:"premium_until,omitempty"`
+	// Used part of the fair use limit, between 0 and 1
+	LimitUsed float64 `json:"limit_used,omitempty"`
+	// Used cloud storage space in bytes
+	SpaceUsed int64 `json:"space_used,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the timestamp, and the used space, which Premiumize sends as floating point number.
+func (i *AccountInfo) UnmarshalJSON(data []byte) error {
+	type accountInfo AccountInfo
+	raw := struct {
+		*accountInfo
+		PremiumUntil jsonutil.UnixTime `json:"premium_until,omitempty"`
+		SpaceUsed    jsonutil.Int      `json:"space_used,omitempty"`
+	}{accountInfo: (*accountInfo)(i)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.PremiumUntil = time.Time(raw.PremiumUntil)
+	i.SpaceUsed = int64(raw.SpaceUsed)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i AccountInfo) MarshalJSON() ([]byte, error) {
+	type accountInfo AccountInfo
+	return json.Marshal(struct {
+		accountInfo
+		PremiumUntil jsonutil.UnixTime `json:"premium_until"`
+	}{accountInfo: accountInfo(i), PremiumUntil: jsonutil.UnixTime(i.PremiumUntil)})
 }
 
 // CachedFile represents a file that's available in Premiumize's cache.
 type CachedFile struct {
+	// Whether a transcoded stream of the file is cached.
+	// It's not taken into account by StreamPolicy, because the stream link is only known after creating the direct download,
+	// and the Download's TranscodeStatus then tells whether the stream can be used.
 	Transcoded bool
 	Filename   string
-	Filesize   string
+	// File size in bytes
+	Filesize int64
+}
+
+// DeviceCode contains the codes of Premiumize's OAuth2 device authorization flow.
+type DeviceCode struct {
+	// Code for polling the token
+	DeviceCode string `json:"device_code,omitempty"`
+	// Code that the user has to enter on the verification URI
+	UserCode string `json:"user_code,omitempty"`
+	// URI where the user has to enter the user code
+	VerificationURI string `json:"verification_uri,omitempty"`
+	// Seconds until the codes expire
+	ExpiresIn int `json:"expires_in,omitempty"`
+	// Polling interval in seconds
+	Interval int `json:"interval,omitempty"`
+	// Point in time when the codes expire. Calculated from ExpiresIn when receiving the codes.
+	Expiry time.Time `json:"-"`
+}
+
+// Token is an OAuth2 token.
+type Token struct {
+	AccessToken string `json:"access_token,omitempty"`
+	// "Bearer"
+	TokenType string `json:"token_type,omitempty"`
+	// Seconds until the access token expires
+	ExpiresIn int `json:"expires_in,omitempty"`
+	// Point in time when the access token expires. Calculated from ExpiresIn when receiving the token. Zero if unknown.
+	// It's (un-)marshalled, so that stored tokens keep their expiry.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// Auth returns the Auth for a Client that uses the token.
+func (t Token) Auth() Auth {
+	return Auth{
+		KeyOrToken: t.AccessToken,
+		OAuth2:     true,
+		Expiry:     t.Expiry,
+	}
+}
+
+// Item is a file or folder in the user's cloud storage.
+type Item struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// "file" or "folder". Not present in the list of all files.
+	Type string `json:"type,omitempty"`
+	// File size in bytes
+	Size int64 `json:"size,omitempty"`
+	// Date when the item was created
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	MimeType  string    `json:"mime_type,omitempty"`
+	// "good", "infected" or "error"
+	VirusScan string `json:"virus_scan,omitempty"`
+	// Status of the transcoding of video files
+	TranscodeStatus TranscodeStatus `json:"transcode_status,omitempty"`
+	// Direct download link. Only present for files.
+	Link string `json:"link,omitempty"`
+	// Link to the transcoded stream. Only present for transcoded files.
+	StreamLink string `json:"stream_link,omitempty"`
+	// !! Only present in the list of all files. Full path of the file.
+	Path string `json:"path,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the timestamp, and the size, which Premiumize sometimes sends as string.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type item Item
+	raw := struct {
+		*item
+		Size      jsonutil.Int      `json:"size,omitempty"`
+		CreatedAt jsonutil.UnixTime `json:"created_at,omitempty"`
+	}{item: (*item)(i)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.Size = int64(raw.Size)
+	i.CreatedAt = time.Time(raw.CreatedAt)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i Item) MarshalJSON() ([]byte, error) {
+	type item Item
+	return json.Marshal(struct {
+		item
+		CreatedAt jsonutil.UnixTime `json:"created_at"`
+	}{item: item(i), CreatedAt: jsonutil.UnixTime(i.CreatedAt)})
+}
+
+// Breadcrumb is one of the folders in the path to a folder.
+type Breadcrumb struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// Folder is a folder in the user's cloud storage, including its content.
+type Folder struct {
+	// Files and folders in the folder
+	Content  []Item `json:"content,omitempty"`
+	Name     string `json:"name,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+	FolderID string `json:"folder_id,omitempty"`
+	// !! Only present if requested. Folders from the root folder to the folder.
+	Breadcrumbs []Breadcrumb `json:"breadcrumbs,omitempty"`
+}
+
+// ItemDetails contains details about a file in the user's cloud storage.
+type ItemDetails struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// "file"
+	Type string `json:"type,omitempty"`
+	// File size in bytes
+	Size int64 `json:"size,omitempty"`
+	// Date when the item was created
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	FolderID  string    `json:"folder_id,omitempty"`
+	// Audio codec of video files
+	ACodec string `json:"acodec,omitempty"`
+	// Video codec of video files
+	VCodec   string `json:"vcodec,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	// Hash for searching subtitles on OpenSubtitles
+	OpenSubtitlesHash string `json:"opensubtitles_hash,omitempty"`
+	// Horizontal resolution of video files
+	ResX int `json:"resx,omitempty"`
+	// Vertical resolution of video files
+	ResY int `json:"resy,omitempty"`
+	// Duration of media files
+	Duration time.Duration `json:"duration,omitempty"`
+	// Status of the transcoding of video files
+	TranscodeStatus TranscodeStatus `json:"transcode_status,omitempty"`
+	// Direct download link
+	Link string `json:"link,omitempty"`
+	// Link to the transcoded stream. Only present for transcoded files.
+	StreamLink string `json:"stream_link,omitempty"`
+	// Bitrate of media files
+	Bitrate float64 `json:"bitrate,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the timestamp, and the numbers, some of which Premiumize sends as strings.
+func (d *ItemDetails) UnmarshalJSON(data []byte) error {
+	type itemDetails ItemDetails
+	raw := struct {
+		*itemDetails
+		Size      jsonutil.Int      `json:"size,omitempty"`
+		CreatedAt jsonutil.UnixTime `json:"created_at,omitempty"`
+		ResX      jsonutil.Int      `json:"resx,omitempty"`
+		ResY      jsonutil.Int      `json:"resy,omitempty"`
+		Duration  jsonutil.Seconds  `json:"duration,omitempty"`
+		Bitrate   jsonutil.Float    `json:"bitrate,omitempty"`
+	}{itemDetails: (*itemDetails)(d)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	d.Size = int64(raw.Size)
+	d.CreatedAt = time.Time(raw.CreatedAt)
+	d.ResX = int(raw.ResX)
+	d.ResY = int(raw.ResY)
+	d.Duration = time.Duration(raw.Duration)
+	d.Bitrate = float64(raw.Bitrate)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d ItemDetails) MarshalJSON() ([]byte, error) {
+	type itemDetails ItemDetails
+	return json.Marshal(struct {
+		itemDetails
+		CreatedAt jsonutil.UnixTime `json:"created_at"`
+		Duration  jsonutil.Seconds  `json:"duration"`
+	}{itemDetails: itemDetails(d), CreatedAt: jsonutil.UnixTime(d.CreatedAt), Duration: jsonutil.Seconds(d.Duration)})
+}
+
+// Services contains the hosters that Premiumize supports.
+type Services struct {
+	// Hosts that Premiumize can download from
+	DirectDL []string `json:"directdl,omitempty"`
+	// Hosts whose downloads Premiumize can cache
+	Cache []string `json:"cache,omitempty"`
+	// Factor with which downloads from a host count towards the fair use limit, by host
+	FairUseFactor map[string]float64 `json:"fairusefactor,omitempty"`
+	// Alternative domains of a host, by host
+	Aliases map[string][]string `json:"aliases,omitempty"`
+	// Regular expressions for links that are supported, by host
+	RegexPatterns map[string][]string `json:"regexpatterns,omitempty"`
 }
@@ -0,0 +1,57 @@
+package premiumize_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/premiumize"
+)
+
+func TestUnmarshalNumbers(t *testing.T) {
+	// Sizes as strings, over 2 GB
+	downloads := []premiumize.Download{}
+	require.NoError(t, json.Unmarshal([]byte(`[{"path":"movie.mkv","size":"5000000000","link":"a"},{"path":"sample.mkv","size":123,"link":"b"}]`), &downloads))
+	require.Equal(t, int64(5000000000), downloads[0].Size)
+	require.Equal(t, int64(123), downloads[1].Size)
+	require.Equal(t, "b", downloads[1].Link)
+
+	info := premiumize.AccountInfo{}
+	require.NoError(t, json.Unmarshal([]byte(`{"customer_id":"123","premium_until":1604756220,"limit_used":0.5,"space_used":5000000000.0}`), &info))
+	require.Equal(t, "123", info.CustomerID)
+	require.True(t, info.PremiumUntil.Equal(time.Unix(1604756220, 0)))
+	require.Equal(t, 0.5, info.LimitUsed)
+	require.Equal(t, int64(5000000000), info.SpaceUsed)
+
+	// Not premium
+	info = premiumize.AccountInfo{}
+	require.NoError(t, json.Unmarshal([]byte(`{"customer_id":"123","premium_until":false}`), &info))
+	require.True(t, info.PremiumUntil.IsZero())
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tt := []struct {
+		name     string
+		json     string
+		newValue func() interface{}
+	}{
+		{"Download", `{"path":"movie.mkv","size":"5000000000","link":"a","stream_link":"b","transcode_status":"finished"}`, func() interface{} { return &premiumize.Download{} }},
+		{"AccountInfo", `{"customer_id":"123","premium_until":1604756220,"limit_used":0.5,"space_used":5000000000.0}`, func() interface{} { return &premiumize.AccountInfo{} }},
+		{"AccountInfo not premium", `{"customer_id":"123","premium_until":false}`, func() interface{} { return &premiumize.AccountInfo{} }},
+		{"Item", `{"id":"1","name":"movie.mkv","type":"file","size":"5000000000","created_at":"1604756220","transcode_status":"running"}`, func() interface{} { return &premiumize.Item{} }},
+		{"ItemDetails", `{"id":"1","name":"movie.mkv","type":"file","size":5000000000,"created_at":1604756220,"resx":"1920","resy":"1080","duration":"5400.5","bitrate":"1234.5","transcode_status":"finished"}`, func() interface{} { return &premiumize.ItemDetails{} }},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := tc.newValue()
+			require.NoError(t, json.Unmarshal([]byte(tc.json), v))
+			data, err := json.Marshal(v)
+			require.NoError(t, err)
+			got := tc.newValue()
+			require.NoError(t, json.Unmarshal(data, got))
+			require.Equal(t, v, got)
+		})
+	}
+}
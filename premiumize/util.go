@@ -1,29 +1,95 @@
 package premiumize
 
 import (
-	"errors"
 	"fmt"
-	"strconv"
+	"path"
+	"strings"
+
+	debrid "github.com/deflix-tv/go-debrid"
 )
 
 // SelectLargestFile returns the largest file in a slice of Download objects.
+//
+// Deprecated: Use SelectFile with debrid.LargestFile, or a more specific selector like debrid.DefaultFileSelector.
 func SelectLargestFile(downloads []Download) (Download, error) {
-	var result Download
-	largestSize := 0
+	return SelectFile(downloads, debrid.LargestFile)
+}
 
-	for _, dl := range downloads {
-		size, err := strconv.Atoi(dl.Size)
-		if err != nil {
-			return Download{}, fmt.Errorf("couldn't parse size: %w", err)
-		}
-		if size > largestSize {
-			result = dl
-			largestSize = size
-		}
+// SelectFile returns the file in a slice of Download objects that the selector selects.
+func SelectFile(downloads []Download, selector debrid.FileSelector) (Download, error) {
+	files := make([]debrid.File, len(downloads))
+	for i, dl := range downloads {
+		files[i] = debrid.File{Path: dl.Path, Size: dl.Size}
+	}
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return Download{}, fmt.Errorf("couldn't select file in downloads: %w", err)
+	}
+	return downloads[i], nil
+}
+
+// StreamPolicy decides whether the transcoded stream link of a file is used instead of its original link,
+// based on which containers and codecs the target client can play.
+// Premiumize transcodes video files to MP4 (H.264 and AAC), which virtually all clients can play.
+type StreamPolicy struct {
+	// File extensions of the containers that the target client can play, like "mp4". Empty means all containers.
+	Containers []string
+	// Video codecs that the target client can play, like "h264". Empty means all codecs.
+	// Only Premiumize's item details contain codecs, so this is ignored for Download objects.
+	VideoCodecs []string
+	// Audio codecs that the target client can play, like "aac". Empty means all codecs.
+	// Only Premiumize's item details contain codecs, so this is ignored for Download objects.
+	AudioCodecs []string
+	// Use the transcoded stream link whenever it's available, even if the target client can play the original file.
+	PreferTranscoded bool
+}
+
+// BrowserStreamPolicy returns a StreamPolicy for clients that only play what web browsers can play natively.
+// Each call returns a new policy, so it can be modified without affecting others.
+func BrowserStreamPolicy() StreamPolicy {
+	return StreamPolicy{
+		Containers:  []string{"mp4", "m4v", "webm"},
+		VideoCodecs: []string{"h264", "vp8", "vp9", "av1"},
+		AudioCodecs: []string{"aac", "mp3", "opus", "vorbis"},
+	}
+}
+
+// SelectStreamLink returns the transcoded stream link of the download if the policy prefers it and the transcoding is finished,
+// and the original link otherwise.
+func SelectStreamLink(dl Download, policy StreamPolicy) string {
+	if dl.StreamLink != "" && dl.TranscodeStatus == TranscodeStatusFinished && policy.prefersTranscoded(dl.Path, "", "") {
+		return dl.StreamLink
 	}
-	if largestSize == 0 {
-		return Download{}, errors.New("couldn't find largest file in downloads")
+	return dl.Link
+}
+
+// SelectItemStreamLink is like SelectStreamLink, but for a file in the user's cloud storage.
+// Other than Download objects, item details contain the codecs of the file, which the policy can take into account.
+func SelectItemStreamLink(details ItemDetails, policy StreamPolicy) string {
+	if details.StreamLink != "" && details.TranscodeStatus == TranscodeStatusFinished && policy.prefersTranscoded(details.Name, details.VCodec, details.ACodec) {
+		return details.StreamLink
 	}
+	return details.Link
+}
 
-	return result, nil
+// prefersTranscoded returns true if the target client is unlikely to play the original file.
+// Empty codecs are regarded as playable, because they're unknown.
+func (p StreamPolicy) prefersTranscoded(fileName, vCodec, aCodec string) bool {
+	if p.PreferTranscoded {
+		return true
+	}
+	ext := strings.TrimPrefix(path.Ext(fileName), ".")
+	return !supports(p.Containers, ext) || !supports(p.VideoCodecs, vCodec) || !supports(p.AudioCodecs, aCodec)
+}
+
+func supports(supported []string, s string) bool {
+	if len(supported) == 0 || s == "" {
+		return true
+	}
+	for _, elem := range supported {
+		if strings.EqualFold(elem, s) {
+			return true
+		}
+	}
+	return false
 }
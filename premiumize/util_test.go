@@ -0,0 +1,35 @@
+package premiumize_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/premiumize"
+)
+
+func TestSelectStreamLink(t *testing.T) {
+	mkv := premiumize.Download{Path: "Movie/movie.mkv", Link: "original", StreamLink: "transcoded", TranscodeStatus: premiumize.TranscodeStatusFinished}
+	mp4 := premiumize.Download{Path: "Movie/movie.MP4", Link: "original", StreamLink: "transcoded", TranscodeStatus: premiumize.TranscodeStatusFinished}
+	running := premiumize.Download{Path: "Movie/movie.mkv", Link: "original", StreamLink: "transcoded", TranscodeStatus: premiumize.TranscodeStatusRunning}
+	noStream := premiumize.Download{Path: "Movie/movie.mkv", Link: "original", TranscodeStatus: premiumize.TranscodeStatusNotApplicable}
+
+	require.Equal(t, "transcoded", premiumize.SelectStreamLink(mkv, premiumize.BrowserStreamPolicy()))
+	require.Equal(t, "original", premiumize.SelectStreamLink(mp4, premiumize.BrowserStreamPolicy()))
+	require.Equal(t, "original", premiumize.SelectStreamLink(running, premiumize.BrowserStreamPolicy()))
+	require.Equal(t, "original", premiumize.SelectStreamLink(noStream, premiumize.BrowserStreamPolicy()))
+	// Empty policy plays everything
+	require.Equal(t, "original", premiumize.SelectStreamLink(mkv, premiumize.StreamPolicy{}))
+	require.Equal(t, "transcoded", premiumize.SelectStreamLink(mp4, premiumize.StreamPolicy{PreferTranscoded: true}))
+
+	// Codecs of items are taken into account
+	details := premiumize.ItemDetails{Name: "movie.mp4", VCodec: "hevc", ACodec: "aac", Link: "original", StreamLink: "transcoded", TranscodeStatus: premiumize.TranscodeStatusFinished}
+	require.Equal(t, "transcoded", premiumize.SelectItemStreamLink(details, premiumize.BrowserStreamPolicy()))
+	details.VCodec = "H264"
+	require.Equal(t, "original", premiumize.SelectItemStreamLink(details, premiumize.BrowserStreamPolicy()))
+
+	// Modifying a policy doesn't affect others
+	policy := premiumize.BrowserStreamPolicy()
+	policy.Containers[0] = "mkv"
+	require.Equal(t, "original", premiumize.SelectStreamLink(mp4, premiumize.BrowserStreamPolicy()))
+}
@@ -12,6 +12,8 @@ import (
 
 	"github.com/tidwall/gjson"
 	"go.uber.org/zap"
+
+	debrid "github.com/deflix-tv/go-debrid"
 )
 
 var zapDebridService = zap.String("debridService", "RealDebrid")
@@ -24,22 +26,33 @@ type ClientOptions struct {
 	Timeout time.Duration
 	// Extra headers to set for HTTP requests
 	ExtraHeaders map[string]string
-	// When setting this to true, the user's original IP address is read from Auth.IP and forwarded to RealDebrid for all POST requests.
+	// When setting this to true, the user's original IP address is read from Auth.IP and forwarded to RealDebrid for all POST and PUT requests.
 	// Only required if the library is used in an app on a machine
 	// whose outgoing IP is different from the machine that's going to request the cached file/stream URL.
 	ForwardOriginIP bool
+	// How long GetInstantDownload waits for RealDebrid to regard an instantly available torrent as downloaded.
+	// RealDebrid briefly reports cached torrents as "queued" after selecting their files, which the default wait time covers.
+	// A negative value disables waiting, so that only the first torrent status counts.
+	InstantDownloadWait time.Duration
 }
 
 // DefaultClientOpts are ClientOptions with reasonable default values.
 var DefaultClientOpts = ClientOptions{
-	BaseURL: "https://api.real-debrid.com/rest/1.0",
-	Timeout: 5 * time.Second,
+	BaseURL:             "https://api.real-debrid.com/rest/1.0",
+	Timeout:             5 * time.Second,
+	InstantDownloadWait: time.Second,
 }
 
+// instantDownloadPollInterval is the interval in which GetInstantDownload checks the torrent status while waiting for it to be downloaded.
+const instantDownloadPollInterval = 250 * time.Millisecond
+
 // Auth carries authentication/authorization info for RealDebrid.
 type Auth struct {
 	// Long lasting API key or expiring OAuth2 access token
 	KeyOrToken string
+	// Optional source of OAuth2 access tokens. If set, KeyOrToken is ignored.
+	// The access token is then refreshed before it expires, and when RealDebrid responds with ErrorBadToken the request is retried once with a refreshed token.
+	TokenSource *TokenSource
 	// The user's original IP. Only required if ClientOptions.ForwardOriginIP is true.
 	IP string
 }
@@ -62,6 +75,9 @@ func NewClient(opts ClientOptions, auth Auth, logger *zap.Logger) *Client {
 	if opts.Timeout == 0 {
 		opts.Timeout = DefaultClientOpts.Timeout
 	}
+	if opts.InstantDownloadWait == 0 {
+		opts.InstantDownloadWait = DefaultClientOpts.InstantDownloadWait
+	}
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -97,10 +113,19 @@ func (c *Client) GetUser(ctx context.Context) (User, error) {
 // For torrents, the torrent must first be added to RealDebrid and a file selected for download, which then leads to such a hoster link.
 // When remote is true, account sharing restrictions are lifted, but it requires separately purchased "sharing traffic".
 func (c *Client) Unrestrict(ctx context.Context, link string, remote bool) (Download, error) {
+	return c.UnrestrictWithPassword(ctx, link, "", remote)
+}
+
+// UnrestrictWithPassword unrestricts a password protected hoster link.
+// An empty password is the same as calling Unrestrict.
+func (c *Client) UnrestrictWithPassword(ctx context.Context, link, password string, remote bool) (Download, error) {
 	c.logger.Debug("Unrestricting link...", zapDebridService)
 
 	data := url.Values{}
 	data.Set("link", link)
+	if password != "" {
+		data.Set("password", password)
+	}
 	if remote {
 		data.Set("remote", "1")
 	}
@@ -133,6 +158,10 @@ func (c *Client) GetTorrentsInfo(ctx context.Context, activeFirst bool) ([]Torre
 		return nil, fmt.Errorf("couldn't get torrents info: %w", err)
 	}
 	info := []TorrentsInfo{}
+	if len(resBytes) == 0 {
+		c.logger.Debug("Got no torrents info", zapDebridService)
+		return info, nil
+	}
 	if err = json.Unmarshal(resBytes, &info); err != nil {
 		return nil, fmt.Errorf("couldn't unmarshal torrents info: %w", err)
 	}
@@ -160,40 +189,68 @@ func (c *Client) GetTorrentInfo(ctx context.Context, id string) (TorrentInfo, er
 }
 
 // GetInstantAvailability fetches and returns info about the instant availability of a torrent.
+// The hashes can be hex or base32 encoded. The returned map uses normalized info hashes as keys (see debrid.NormalizeInfoHash).
+// Only the first variant of instantly available files is returned per torrent. Use GetInstantAvailabilityVariants to get all of them.
 func (c *Client) GetInstantAvailability(ctx context.Context, hashes ...string) (map[string]InstantAvailability, error) {
+	variantsByHash, err := c.GetInstantAvailabilityVariants(ctx, hashes...)
+	if err != nil {
+		return nil, err
+	}
+	availabilities := make(map[string]InstantAvailability, len(variantsByHash))
+	for hash, variants := range variantsByHash {
+		availabilities[hash] = variants[0]
+	}
+	return availabilities, nil
+}
+
+// GetInstantAvailabilityVariants fetches and returns info about the instant availability of a torrent.
+// RealDebrid can have multiple variants per torrent, each being a set of files that are cached together.
+// Selecting exactly the files of one variant (see InstantAvailability.FileIDs and SelectFiles) leads to an instant download.
+// The hashes can be hex or base32 encoded. The returned map uses normalized info hashes as keys (see debrid.NormalizeInfoHash)
+// and only contains torrents with at least one variant.
+func (c *Client) GetInstantAvailabilityVariants(ctx context.Context, hashes ...string) (map[string][]InstantAvailability, error) {
 	c.logger.Debug("Getting instant availability...", zapDebridService)
 
 	var hashParams string
 	for _, hash := range hashes {
-		hashParams += "/" + hash
+		normalizedHash, err := debrid.NormalizeInfoHash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't normalize hash %q: %w", hash, err)
+		}
+		hashParams += "/" + normalizedHash
 	}
 	resBytes, err := c.get(ctx, c.opts.BaseURL+"/torrents/instantAvailability"+hashParams, nil)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't get instant availability: %w", err)
 	}
-	availabilities := make(map[string]InstantAvailability, len(hashes))
+	availabilities := make(map[string][]InstantAvailability, len(hashes))
 	gjson.ParseBytes(resBytes).ForEach(func(key, value gjson.Result) bool {
-		availableHash := key.String()
-		// We check the original hash, so that our result has the same upper-/lowercase per hash
-		for _, hash := range hashes {
-			if strings.EqualFold(hash, availableHash) {
-				availableHash = hash
-				break
-			}
+		availableHash, err := debrid.NormalizeInfoHash(key.String())
+		if err != nil {
+			c.logger.Error("Couldn't normalize available hash", zap.Error(err), zap.String("hash", key.String()), zapDebridService)
+			return true
 		}
-		availability := InstantAvailability{}
-		value.Get("rd.0").ForEach(func(key, value gjson.Result) bool {
-			availableFile := AvailableFile{}
-			if err := json.Unmarshal([]byte(value.Raw), &availableFile); err != nil {
-				c.logger.Error("Couldn't unmarshal available file", zap.Error(err), zap.String("availableFile", value.Raw), zapDebridService)
+		var variants []InstantAvailability
+		value.Get("rd").ForEach(func(_, variantValue gjson.Result) bool {
+			availability := InstantAvailability{}
+			variantValue.ForEach(func(key, value gjson.Result) bool {
+				availableFile := AvailableFile{}
+				if err := json.Unmarshal([]byte(value.Raw), &availableFile); err != nil {
+					c.logger.Error("Couldn't unmarshal available file", zap.Error(err), zap.String("availableFile", value.Raw), zapDebridService)
+					return true
+				}
+				availability[int(key.Int())] = availableFile
+				// Continue ForEach
 				return true
+			})
+			if len(availability) > 0 {
+				variants = append(variants, availability)
 			}
-			availability[int(key.Int())] = availableFile
 			// Continue ForEach
 			return true
 		})
-		if len(availability) > 0 {
-			availabilities[availableHash] = availability
+		if len(variants) > 0 {
+			availabilities[availableHash] = variants
 		}
 		// Continue ForEach
 		return true
@@ -219,6 +276,22 @@ func (c *Client) AddMagnet(ctx context.Context, magnet string) (string, error) {
 	return id, nil
 }
 
+// AddTorrent adds a torrent to RealDebrid via the content of a .torrent file.
+// Like with AddMagnet, the returned ID must be used to select the files to download.
+// The torrent can be parsed with debrid.ParseTorrent beforehand, for example to get its info hash and file list.
+func (c *Client) AddTorrent(ctx context.Context, torrent []byte) (string, error) {
+	c.logger.Debug("Adding torrent...", zapDebridService)
+
+	resBytes, err := c.put(ctx, c.opts.BaseURL+"/torrents/addTorrent", torrent)
+	if err != nil {
+		return "", fmt.Errorf("couldn't add torrent: %w", err)
+	}
+	id := gjson.GetBytes(resBytes, "id").String()
+
+	c.logger.Debug("Added torrent", zap.String("id", id), zapDebridService)
+	return id, nil
+}
+
 // SelectFiles starts downloading the selected files from a torrent that was previously added to RealDebrid for the specific user.
 func (c *Client) SelectFiles(ctx context.Context, torrentID string, fileIDs ...int) error {
 	c.logger.Debug("Selecting files...", zapDebridService)
@@ -251,3 +324,143 @@ func (c *Client) DeleteTorrent(ctx context.Context, id string) error {
 	c.logger.Debug("Deleted torrent", zapDebridService)
 	return nil
 }
+
+// GetActiveTorrentsCount fetches and returns the number of currently active torrents and the user's limit of active torrents.
+func (c *Client) GetActiveTorrentsCount(ctx context.Context) (ActiveCount, error) {
+	c.logger.Debug("Getting active torrents count...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/torrents/activeCount", nil)
+	if err != nil {
+		return ActiveCount{}, fmt.Errorf("couldn't get active torrents count: %w", err)
+	}
+	count := ActiveCount{}
+	if err = json.Unmarshal(resBytes, &count); err != nil {
+		return ActiveCount{}, fmt.Errorf("couldn't unmarshal active torrents count: %w", err)
+	}
+
+	c.logger.Debug("Got active torrents count", zap.String("count", fmt.Sprintf("%+v", count)), zapDebridService)
+	return count, nil
+}
+
+// GetAvailableHosts fetches and returns the hosts that torrents can be downloaded to.
+func (c *Client) GetAvailableHosts(ctx context.Context) ([]AvailableHost, error) {
+	c.logger.Debug("Getting available hosts...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/torrents/availableHosts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get available hosts: %w", err)
+	}
+	hosts := []AvailableHost{}
+	if err = json.Unmarshal(resBytes, &hosts); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal available hosts: %w", err)
+	}
+
+	c.logger.Debug("Got available hosts", zap.String("hosts", fmt.Sprintf("%+v", hosts)), zapDebridService)
+	return hosts, nil
+}
+
+// GetInstantDownload adds a torrent to RealDebrid and returns the direct download of one of its instantly available files.
+// The variants must be the ones from GetInstantAvailabilityVariants for the torrent.
+// The selector selects the file among all instantly available files, and then exactly the files of the variant containing it are selected,
+// so that RealDebrid doesn't have to download anything.
+// RealDebrid briefly reports cached torrents as "queued" or "downloading", so the torrent status is polled for up to ClientOptions.InstantDownloadWait.
+// If RealDebrid still doesn't regard the torrent as downloaded afterwards, or the torrent failed, the torrent is deleted and ErrorNotInstantlyAvailable is returned,
+// instead of waiting for a download.
+func (c *Client) GetInstantDownload(ctx context.Context, magnet string, variants []InstantAvailability, selector debrid.FileSelector, remote bool) (InstantDownload, error) {
+	// Select file among all instantly available ones
+	var fileIDs []int
+	var files []debrid.File
+	seen := map[int]struct{}{}
+	for _, variant := range variants {
+		for _, id := range variant.FileIDs() {
+			if _, found := seen[id]; !found {
+				seen[id] = struct{}{}
+				fileIDs = append(fileIDs, id)
+				files = append(files, debrid.File{Path: variant[id].Filename, Size: variant[id].Filesize})
+			}
+		}
+	}
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return InstantDownload{}, fmt.Errorf("couldn't select instantly available file: %w", err)
+	}
+	fileID := fileIDs[i]
+	variant, _ := SelectVariant(variants, fileID)
+	variantFileIDs := variant.FileIDs()
+
+	torrentID, err := c.AddMagnet(ctx, magnet)
+	if err != nil {
+		return InstantDownload{}, err
+	}
+	// From here on we delete the torrent on errors, so that it doesn't remain in the user's torrents
+	deleteTorrent := func() {
+		if err := c.DeleteTorrent(ctx, torrentID); err != nil {
+			c.logger.Error("Couldn't delete torrent", zap.Error(err), zap.String("torrentID", torrentID), zapDebridService)
+		}
+	}
+	if err = c.SelectFiles(ctx, torrentID, variantFileIDs...); err != nil {
+		deleteTorrent()
+		return InstantDownload{}, err
+	}
+	info, err := c.waitForDownloaded(ctx, torrentID)
+	if err != nil {
+		deleteTorrent()
+		return InstantDownload{}, err
+	}
+
+	// The links are in the order of the selected files
+	var link string
+	if len(info.Links) == len(variantFileIDs) {
+		for j, id := range variantFileIDs {
+			if id == fileID {
+				link = info.Links[j]
+				break
+			}
+		}
+	} else if len(info.Links) == 1 {
+		link = info.Links[0]
+	}
+	if link == "" {
+		deleteTorrent()
+		return InstantDownload{}, fmt.Errorf("couldn't find link for file %v in torrent info", fileID)
+	}
+
+	dl, err := c.Unrestrict(ctx, link, remote)
+	if err != nil {
+		deleteTorrent()
+		return InstantDownload{}, err
+	}
+
+	return InstantDownload{
+		TorrentID: torrentID,
+		FileID:    fileID,
+		Download:  dl,
+	}, nil
+}
+
+// waitForDownloaded polls the torrent info until RealDebrid regards the torrent as downloaded.
+// It returns ErrorNotInstantlyAvailable if the torrent failed or isn't downloaded within ClientOptions.InstantDownloadWait.
+func (c *Client) waitForDownloaded(ctx context.Context, torrentID string) (TorrentInfo, error) {
+	deadline := time.Now().Add(c.opts.InstantDownloadWait)
+	for {
+		info, err := c.GetTorrentInfo(ctx, torrentID)
+		if err != nil {
+			return TorrentInfo{}, err
+		}
+		switch info.Status {
+		case "downloaded":
+			return info, nil
+		case "magnet_error", "error", "virus", "dead":
+			return TorrentInfo{}, fmt.Errorf("%w: torrent status is %v", ErrorNotInstantlyAvailable, info.Status)
+		}
+		if time.Now().Add(instantDownloadPollInterval).After(deadline) {
+			return TorrentInfo{}, fmt.Errorf("%w: torrent status is still %v", ErrorNotInstantlyAvailable, info.Status)
+		}
+		c.logger.Debug("Waiting for torrent to be downloaded", zap.String("status", info.Status), zapDebridService)
+		select {
+		case <-ctx.Done():
+			return TorrentInfo{}, ctx.Err()
+		case <-time.After(instantDownloadPollInterval):
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	debrid "github.com/deflix-tv/go-debrid"
 	"github.com/deflix-tv/go-debrid/realdebrid"
 )
 
@@ -50,6 +51,26 @@ func TestClient(t *testing.T) {
 	require.True(t, found)
 	require.NotEmpty(t, availableFile.Filesize)
 
+	// Get all instant availability variants
+	variantsByHash, err := client.GetInstantAvailabilityVariants(ctx, nightOfTheLivingDeadHash)
+	require.NoError(t, err)
+	fmt.Printf("Availability variants: %+v\n", variantsByHash)
+	variants, found := variantsByHash[nightOfTheLivingDeadHash]
+	require.True(t, found)
+	require.NotEmpty(t, variants)
+	variant, found := realdebrid.SelectVariant(variants, 1)
+	require.True(t, found)
+	require.Contains(t, variant.FileIDs(), 1)
+
+	// Get instant download
+	instantDL, err := client.GetInstantDownload(ctx, nightOfTheLivingDeadMagnet, variants, debrid.DefaultFileSelector, false)
+	require.NoError(t, err)
+	fmt.Printf("Instant download: %+v\n", instantDL)
+	require.Equal(t, 1, instantDL.FileID)
+	require.NotEmpty(t, instantDL.Download.Download)
+	err = client.DeleteTorrent(ctx, instantDL.TorrentID)
+	require.NoError(t, err)
+
 	// Add magnet
 	torrentID, err := client.AddMagnet(ctx, nightOfTheLivingDeadMagnet)
 	require.NoError(t, err)
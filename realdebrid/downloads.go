@@ -0,0 +1,48 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// GetDownloads fetches and returns the user's list of unrestricted links.
+// Limit must be between 1 and 5000. An empty list is returned when the offset is beyond the last download.
+func (c *Client) GetDownloads(ctx context.Context, offset, limit int) ([]Download, error) {
+	c.logger.Debug("Getting downloads...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("offset", strconv.Itoa(offset))
+	data.Set("limit", strconv.Itoa(limit))
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/downloads", data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get downloads: %w", err)
+	}
+	downloads := []Download{}
+	if len(resBytes) == 0 {
+		c.logger.Debug("Got no downloads", zapDebridService)
+		return downloads, nil
+	}
+	if err = json.Unmarshal(resBytes, &downloads); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal downloads: %w", err)
+	}
+
+	c.logger.Debug("Got downloads", zap.String("downloads", fmt.Sprintf("%+v", downloads)), zapDebridService)
+	return downloads, nil
+}
+
+// DeleteDownload deletes a link from the user's list of unrestricted links.
+func (c *Client) DeleteDownload(ctx context.Context, id string) error {
+	c.logger.Debug("Deleting download...", zapDebridService)
+
+	if err := c.delete(ctx, c.opts.BaseURL+"/downloads/delete/"+id); err != nil {
+		return fmt.Errorf("couldn't delete download: %w", err)
+	}
+
+	c.logger.Debug("Deleted download", zapDebridService)
+	return nil
+}
@@ -0,0 +1,399 @@
+package realdebrid_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+	"github.com/deflix-tv/go-debrid/internal/fakeserver"
+	"github.com/deflix-tv/go-debrid/realdebrid"
+)
+
+// fakeResponse is a canned response of the fake RealDebrid server.
+type fakeResponse = fakeserver.Response
+
+// newFakeClient starts a fake RealDebrid server that responds to requests like "GET /hosts" with the given responses,
+// and returns a client that sends its requests to it.
+func newFakeClient(t *testing.T, responses map[string]fakeResponse) *realdebrid.Client {
+	return newFakeClientWithOpts(t, realdebrid.DefaultClientOpts, realdebrid.Auth{KeyOrToken: "123"}, responses)
+}
+
+// newFakeClientWithOpts is like newFakeClient, but uses the given client options and auth.
+// The base URL of the options is replaced by the one of the fake server.
+func newFakeClientWithOpts(t *testing.T, opts realdebrid.ClientOptions, auth realdebrid.Auth, responses map[string]fakeResponse) *realdebrid.Client {
+	server := fakeserver.New(t, fakeserver.ByMethodAndPath, func(t *testing.T, r *http.Request) {
+		assert.Equal(t, "Bearer 123", r.Header.Get("Authorization"))
+	}, responses)
+	opts.BaseURL = server.URL
+	return realdebrid.NewClient(opts, auth, nil)
+}
+
+func TestHosts(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /hosts": {Status: http.StatusOK, Body: `{"1fichier.com":{"id":"1F","name":"1fichier","image":"https://fcdn.real-debrid.com/0818/images/hosters/1fichier.png"}}`},
+		"GET /hosts/status": {Status: http.StatusOK, Body: `{"1fichier.com":{"id":"1F","name":"1fichier","supported":1,"status":"up","check_time":"2020-11-07T13:37:00.000Z",` +
+			`"competitors_status":{"alldebrid.com":{"status":"down","check_time":"2020-11-07T13:30:00.000Z"}}}}`},
+		"GET /hosts/regex":   {Status: http.StatusOK, Body: `["/(http|https):\\/\\/(\\w+\\.)?1fichier\\.com\\/\\?.*/"]`},
+		"GET /hosts/domains": {Status: http.StatusOK, Body: `["1fichier.com","uptobox.com"]`},
+		"GET /time/iso":      {Status: http.StatusOK, Body: `2020-11-07T14:37:00+0100`},
+	})
+	ctx := context.Background()
+
+	hosts, err := client.GetHosts(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1fichier", hosts["1fichier.com"].Name)
+
+	statuses, err := client.GetHostsStatus(ctx)
+	require.NoError(t, err)
+	status := statuses["1fichier.com"]
+	require.Equal(t, "up", status.Status)
+	require.Equal(t, 1, status.Supported)
+	require.True(t, status.CheckTime.Equal(time.Date(2020, 11, 7, 13, 37, 0, 0, time.UTC)))
+	require.Equal(t, "down", status.CompetitorsStatus["alldebrid.com"].Status)
+
+	regexes, err := client.GetHostsRegex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{`/(http|https):\/\/(\w+\.)?1fichier\.com\/\?.*/`}, regexes)
+
+	domains, err := client.GetHostsDomains(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1fichier.com", "uptobox.com"}, domains)
+
+	serverTime, err := client.GetTime(ctx)
+	require.NoError(t, err)
+	require.True(t, serverTime.Equal(time.Date(2020, 11, 7, 13, 37, 0, 0, time.UTC)))
+}
+
+func TestTraffic(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /traffic": {Status: http.StatusOK, Body: `{"uptobox.com":{"left":1000,"bytes":500,"links":2,"limit":1500,"type":"bytes","extra":0,"reset":"daily"}}`},
+		"GET /traffic/details": {Status: http.StatusOK, Body: `{"2020-11-07":{"host":{"uptobox.com":500},"bytes":500}}`, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "2020-11-01", r.URL.Query().Get("start"))
+			assert.Equal(t, "2020-11-07", r.URL.Query().Get("end"))
+		}},
+	})
+	ctx := context.Background()
+
+	traffic, err := client.GetTraffic(ctx)
+	require.NoError(t, err)
+	require.Equal(t, realdebrid.Traffic{Left: 1000, Bytes: 500, Links: 2, Limit: 1500, Type: "bytes", Reset: "daily"}, traffic["uptobox.com"])
+
+	start := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 11, 7, 0, 0, 0, 0, time.UTC)
+	details, err := client.GetTrafficDetails(ctx, start, end)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), details["2020-11-07"].Bytes)
+	require.Equal(t, int64(500), details["2020-11-07"].Host["uptobox.com"])
+}
+
+func TestDownloads(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /downloads": {Status: http.StatusOK, Body: `[{"id":"ABC","filename":"movie.mkv","filesize":123,"generated":"2020-11-07T13:37:00.000Z"}]`, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "0", r.URL.Query().Get("offset"))
+			assert.Equal(t, "50", r.URL.Query().Get("limit"))
+		}},
+		"DELETE /downloads/delete/ABC": {Status: http.StatusNoContent},
+	})
+	ctx := context.Background()
+
+	downloads, err := client.GetDownloads(ctx, 0, 50)
+	require.NoError(t, err)
+	require.Len(t, downloads, 1)
+	require.Equal(t, "ABC", downloads[0].ID)
+	require.True(t, downloads[0].Generated.Equal(time.Date(2020, 11, 7, 13, 37, 0, 0, time.UTC)))
+
+	err = client.DeleteDownload(ctx, "ABC")
+	require.NoError(t, err)
+
+	// RealDebrid responds with 204 No Content instead of an empty list
+	client = newFakeClient(t, map[string]fakeResponse{
+		"GET /downloads": {Status: http.StatusNoContent},
+	})
+	downloads, err = client.GetDownloads(ctx, 100, 50)
+	require.NoError(t, err)
+	require.Empty(t, downloads)
+}
+
+func TestSettings(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /settings": {Status: http.StatusOK, Body: `{"download_ports":["normal","secured"],"download_port":"secured","locales":{"en":"English"},"locale":"en","streaming_quality":"original"}`},
+		"POST /settings/update": {Status: http.StatusNoContent, Check: func(t *testing.T, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "locale", r.PostForm.Get("setting_name"))
+			assert.Equal(t, "fr", r.PostForm.Get("setting_value"))
+		}},
+		"POST /settings/convertPoints": {Status: http.StatusNoContent},
+	})
+	ctx := context.Background()
+
+	settings, err := client.GetSettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"normal", "secured"}, settings.DownloadPorts)
+	require.Equal(t, "secured", settings.DownloadPort)
+	require.Equal(t, "English", settings.Locales["en"])
+	require.Equal(t, "original", settings.StreamingQuality)
+
+	err = client.UpdateSetting(ctx, "locale", "fr")
+	require.NoError(t, err)
+
+	err = client.ConvertPoints(ctx)
+	require.NoError(t, err)
+}
+
+func TestTorrentsMeta(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /torrents/activeCount":    {Status: http.StatusOK, Body: `{"nb":3,"limit":25}`},
+		"GET /torrents/availableHosts": {Status: http.StatusOK, Body: `[{"host":"real-debrid.com","max_file_size":2000}]`},
+	})
+	ctx := context.Background()
+
+	count, err := client.GetActiveTorrentsCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, realdebrid.ActiveCount{Count: 3, Limit: 25}, count)
+
+	hosts, err := client.GetAvailableHosts(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []realdebrid.AvailableHost{{Host: "real-debrid.com", MaxFileSize: 2000}}, hosts)
+}
+
+func TestErrorResponse(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /settings": {Status: http.StatusUnauthorized, Body: `{"error":"bad_token","error_code":8}`},
+	})
+	_, err := client.GetSettings(context.Background())
+	require.ErrorIs(t, err, realdebrid.ErrorBadToken)
+}
+
+func TestUnrestrictExtras(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"POST /unrestrict/check": {Status: http.StatusOK, Body: `{"host":"1fichier.com","link":"https://1fichier.com/?abc","filename":"movie.mkv","filesize":123,"supported":1}`, Check: func(t *testing.T, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "https://1fichier.com/?abc", r.PostForm.Get("link"))
+			assert.Equal(t, "secret", r.PostForm.Get("password"))
+		}},
+		"POST /unrestrict/link": {Status: http.StatusOK, Body: `{"id":"ABC","filename":"movie.mkv","download":"https://foo.download.real-debrid.com/d/ABC/movie.mkv"}`, Check: func(t *testing.T, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "secret", r.PostForm.Get("password"))
+		}},
+		"POST /unrestrict/folder":        {Status: http.StatusOK, Body: `["https://1fichier.com/?abc","https://1fichier.com/?def"]`},
+		"POST /unrestrict/containerLink": {Status: http.StatusOK, Body: `["https://1fichier.com/?abc"]`},
+		"PUT /unrestrict/containerFile": {Status: http.StatusCreated, Body: `["https://1fichier.com/?def"]`, Check: func(t *testing.T, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "container", string(body))
+		}},
+	})
+	ctx := context.Background()
+
+	check, err := client.CheckLink(ctx, "https://1fichier.com/?abc", "secret")
+	require.NoError(t, err)
+	require.Equal(t, realdebrid.LinkCheck{Host: "1fichier.com", Link: "https://1fichier.com/?abc", Filename: "movie.mkv", Filesize: 123, Supported: 1}, check)
+
+	dl, err := client.UnrestrictWithPassword(ctx, "https://1fichier.com/?abc", "secret", false)
+	require.NoError(t, err)
+	require.Equal(t, "ABC", dl.ID)
+
+	links, err := client.UnrestrictFolder(ctx, "https://1fichier.com/dir/123")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://1fichier.com/?abc", "https://1fichier.com/?def"}, links)
+
+	links, err = client.UnrestrictContainerLink(ctx, "https://example.com/links.dlc")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://1fichier.com/?abc"}, links)
+
+	links, err = client.UnrestrictContainerFile(ctx, []byte("container"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://1fichier.com/?def"}, links)
+}
+
+func TestStreaming(t *testing.T) {
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /streaming/transcode/ABC": {Status: http.StatusOK, Body: `{"apple":{"full":"https://foo.download.real-debrid.com/t/ABC/full.m3u8"},` +
+			`"dash":{"full":"https://foo.download.real-debrid.com/t/ABC/full.mpd"},"liveMP4":{"full":"https://foo.download.real-debrid.com/t/ABC/full.mp4"},"h264WebM":{}}`},
+		"GET /streaming/mediaInfos/ABC": {Status: http.StatusOK, Body: `{"filename":"movie.mkv","type":"movie","duration":5766.5,"bitrate":4000000,"size":2883250000,` +
+			`"details":{"video":{"und1":{"stream":"0:0","lang":"Unknown","lang_iso":"und","codec":"h264","colorspace":"yuv420p","width":1920,"height":1080}},` +
+			`"audio":{"eng1":{"stream":"0:1","lang":"English","lang_iso":"eng","codec":"ac3","sampling":48000,"channels":5.1}},"subtitles":[]},` +
+			`"availableFormats":{"apple":"m3u8"}}`},
+	})
+	ctx := context.Background()
+
+	links, err := client.GetTranscodeLinks(ctx, "ABC")
+	require.NoError(t, err)
+	require.Equal(t, "https://foo.download.real-debrid.com/t/ABC/full.m3u8", links.HLS["full"])
+	require.Equal(t, "https://foo.download.real-debrid.com/t/ABC/full.mpd", links.DASH["full"])
+	require.Equal(t, "https://foo.download.real-debrid.com/t/ABC/full.mp4", links.LiveMP4["full"])
+	require.Empty(t, links.H264WebM)
+
+	infos, err := client.GetMediaInfos(ctx, "ABC")
+	require.NoError(t, err)
+	require.Equal(t, "movie", infos.Type)
+	require.Equal(t, 5766500*time.Millisecond, infos.Duration)
+	require.Equal(t, realdebrid.VideoTrack{Stream: "0:0", Lang: "Unknown", LangISO: "und", Codec: "h264", Colorspace: "yuv420p", Width: 1920, Height: 1080}, infos.Details.Video["und1"])
+	require.Equal(t, 5.1, infos.Details.Audio["eng1"].Channels)
+	require.Empty(t, infos.Details.Subtitles)
+	require.Equal(t, "m3u8", infos.AvailableFormats["apple"])
+
+	// Track lists instead of objects
+	details := realdebrid.MediaDetails{}
+	err = json.Unmarshal([]byte(`{"video":[],"audio":[],"subtitles":[{"stream":"0:2","lang_iso":"eng","type":"SRT"}]}`), &details)
+	require.NoError(t, err)
+	require.Equal(t, "SRT", details.Subtitles["0"].Type)
+}
+
+func TestAddTorrent(t *testing.T) {
+	torrent := []byte("d4:infod4:name5:Moviee")
+	client := newFakeClient(t, map[string]fakeResponse{
+		"PUT /torrents/addTorrent": {Status: http.StatusCreated, Body: `{"id":"ABC","uri":"https://api.real-debrid.com/rest/1.0/torrents/info/ABC"}`, Check: func(t *testing.T, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, torrent, body)
+			assert.Empty(t, r.URL.Query().Get("ip"))
+		}},
+	})
+	ctx := context.Background()
+
+	id, err := client.AddTorrent(ctx, torrent)
+	require.NoError(t, err)
+	require.Equal(t, "ABC", id)
+
+	// The original IP is forwarded
+	opts := realdebrid.DefaultClientOpts
+	opts.ForwardOriginIP = true
+	client = newFakeClientWithOpts(t, opts, realdebrid.Auth{KeyOrToken: "123", IP: "1.2.3.4"}, map[string]fakeResponse{
+		"PUT /torrents/addTorrent": {Status: http.StatusCreated, Body: `{"id":"ABC"}`, Check: func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "1.2.3.4", r.URL.Query().Get("ip"))
+		}},
+	})
+	id, err = client.AddTorrent(ctx, torrent)
+	require.NoError(t, err)
+	require.Equal(t, "ABC", id)
+
+	// Missing IP
+	client = newFakeClientWithOpts(t, opts, realdebrid.Auth{KeyOrToken: "123"}, nil)
+	_, err = client.AddTorrent(ctx, torrent)
+	require.Error(t, err)
+
+	// Invalid torrent
+	client = newFakeClient(t, map[string]fakeResponse{
+		"PUT /torrents/addTorrent": {Status: http.StatusBadRequest, Body: `{"error":"bad_request","error_code":-1}`},
+	})
+	_, err = client.AddTorrent(ctx, []byte("foo"))
+	require.ErrorIs(t, err, realdebrid.ErrorBadRequest)
+}
+
+func TestInstantAvailabilityVariants(t *testing.T) {
+	hash := "50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139"
+	// The second hash is base32 encoded in the request, and RealDebrid responds with lower case hex hashes
+	otherHash := "DEADBEEFDEADBEEFDEADBEEFDEADBEEFDEADBEEF"
+	otherHashBase32 := "32W35366VW7O7XVNX3X55LN657PK3PXP"
+	client := newFakeClient(t, map[string]fakeResponse{
+		"GET /torrents/instantAvailability/" + hash + "/" + otherHash: {Status: http.StatusOK, Body: `{` +
+			`"50b7dafb7137cbecf045f78e8efbe4ac1a90d139":{"rd":[` +
+			`{"1":{"filename":"movie.mp4","filesize":828760756},"2":{"filename":"poster.jpg","filesize":58132}},` +
+			`{"1":{"filename":"movie.mp4","filesize":828760756}},` +
+			`{"2":{"filename":"poster.jpg","filesize":58132}}` +
+			`]},` +
+			`"deadbeefdeadbeefdeadbeefdeadbeefdeadbeef":[]}`},
+	})
+	ctx := context.Background()
+
+	variantsByHash, err := client.GetInstantAvailabilityVariants(ctx, strings.ToLower(hash), otherHashBase32)
+	require.NoError(t, err)
+	// Torrents without variants aren't part of the result
+	require.Len(t, variantsByHash, 1)
+	variants := variantsByHash[hash]
+	require.Len(t, variants, 3)
+	require.Equal(t, []int{1, 2}, variants[0].FileIDs())
+	require.Equal(t, int64(828760756), variants[0][1].Filesize)
+
+	// The smallest variant that contains the file
+	variant, found := realdebrid.SelectVariant(variants, 1)
+	require.True(t, found)
+	require.Equal(t, []int{1}, variant.FileIDs())
+	variant, found = realdebrid.SelectVariant(variants, 2)
+	require.True(t, found)
+	require.Equal(t, []int{2}, variant.FileIDs())
+	_, found = realdebrid.SelectVariant(variants, 3)
+	require.False(t, found)
+
+	// Invalid hashes aren't sent
+	_, err = client.GetInstantAvailabilityVariants(ctx, "foo")
+	require.Error(t, err)
+}
+
+func TestGetInstantDownload(t *testing.T) {
+	magnet := "magnet:?xt=urn:btih:50B7DAFB7137CBECF045F78E8EFBE4AC1A90D139"
+	variants := []realdebrid.InstantAvailability{
+		{1: {Filename: "movie.mp4", Filesize: 828760756}, 2: {Filename: "poster.jpg", Filesize: 58132}},
+		{1: {Filename: "movie.mp4", Filesize: 828760756}},
+	}
+	opts := realdebrid.DefaultClientOpts
+	opts.InstantDownloadWait = 2 * time.Second
+	auth := realdebrid.Auth{KeyOrToken: "123"}
+	var deleted int32
+	responses := func(infoBodies ...string) map[string]fakeResponse {
+		return map[string]fakeResponse{
+			"POST /torrents/addMagnet": {Status: http.StatusCreated, Body: `{"id":"ABC"}`},
+			"POST /torrents/selectFiles/ABC": {Status: http.StatusNoContent, Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				// Exactly the files of the smallest variant containing the selected file
+				assert.Equal(t, "1", r.PostForm.Get("files"))
+			}},
+			"GET /torrents/info/ABC": {Status: http.StatusOK, Bodies: infoBodies},
+			"POST /unrestrict/link": {Status: http.StatusOK, Body: `{"id":"DL","filename":"movie.mp4","filesize":828760756,"download":"https://download.real-debrid.com/d/DL/movie.mp4"}`, Check: func(t *testing.T, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "https://real-debrid.com/d/LINK1", r.PostForm.Get("link"))
+			}},
+			"DELETE /torrents/delete/ABC": {Status: http.StatusNoContent, Check: func(t *testing.T, r *http.Request) {
+				atomic.AddInt32(&deleted, 1)
+			}},
+		}
+	}
+	downloaded := `{"id":"ABC","status":"downloaded","links":["https://real-debrid.com/d/LINK1"]}`
+	ctx := context.Background()
+
+	// Downloaded right away
+	client := newFakeClientWithOpts(t, opts, auth, responses(downloaded))
+	instantDL, err := client.GetInstantDownload(ctx, magnet, variants, debrid.LargestFile, false)
+	require.NoError(t, err)
+	require.Equal(t, "ABC", instantDL.TorrentID)
+	require.Equal(t, 1, instantDL.FileID)
+	require.Equal(t, "https://download.real-debrid.com/d/DL/movie.mp4", instantDL.Download.Download)
+	require.Equal(t, int32(0), atomic.LoadInt32(&deleted))
+
+	// Transient statuses of cached torrents
+	client = newFakeClientWithOpts(t, opts, auth, responses(`{"id":"ABC","status":"queued"}`, `{"id":"ABC","status":"downloading","progress":100}`, downloaded))
+	instantDL, err = client.GetInstantDownload(ctx, magnet, variants, debrid.LargestFile, false)
+	require.NoError(t, err)
+	require.Equal(t, "https://download.real-debrid.com/d/DL/movie.mp4", instantDL.Download.Download)
+	require.Equal(t, int32(0), atomic.LoadInt32(&deleted))
+
+	// Not downloaded within the wait time
+	opts.InstantDownloadWait = 600 * time.Millisecond
+	client = newFakeClientWithOpts(t, opts, auth, responses(`{"id":"ABC","status":"downloading","progress":3}`))
+	_, err = client.GetInstantDownload(ctx, magnet, variants, debrid.LargestFile, false)
+	require.ErrorIs(t, err, realdebrid.ErrorNotInstantlyAvailable)
+	require.Equal(t, int32(1), atomic.LoadInt32(&deleted))
+
+	// Waiting disabled
+	opts.InstantDownloadWait = -1
+	client = newFakeClientWithOpts(t, opts, auth, responses(`{"id":"ABC","status":"queued"}`, downloaded))
+	_, err = client.GetInstantDownload(ctx, magnet, variants, debrid.LargestFile, false)
+	require.ErrorIs(t, err, realdebrid.ErrorNotInstantlyAvailable)
+	require.Equal(t, int32(2), atomic.LoadInt32(&deleted))
+
+	// Failed torrents aren't waited for
+	client = newFakeClientWithOpts(t, opts, auth, responses(`{"id":"ABC","status":"magnet_error"}`))
+	_, err = client.GetInstantDownload(ctx, magnet, variants, debrid.LargestFile, false)
+	require.ErrorIs(t, err, realdebrid.ErrorNotInstantlyAvailable)
+	require.Equal(t, int32(3), atomic.LoadInt32(&deleted))
+}
@@ -0,0 +1,98 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetHosts fetches and returns the hosters that are supported by RealDebrid, mapped by their domain.
+func (c *Client) GetHosts(ctx context.Context) (map[string]Host, error) {
+	c.logger.Debug("Getting hosts...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get hosts: %w", err)
+	}
+	hosts := map[string]Host{}
+	if err = json.Unmarshal(resBytes, &hosts); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal hosts: %w", err)
+	}
+
+	c.logger.Debug("Got hosts", zap.Int("hostCount", len(hosts)), zapDebridService)
+	return hosts, nil
+}
+
+// GetHostsStatus fetches and returns the status of the hosters that are supported by RealDebrid, mapped by their domain.
+func (c *Client) GetHostsStatus(ctx context.Context) (map[string]HostStatus, error) {
+	c.logger.Debug("Getting hosts status...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts/status", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get hosts status: %w", err)
+	}
+	statuses := map[string]HostStatus{}
+	if err = json.Unmarshal(resBytes, &statuses); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal hosts status: %w", err)
+	}
+
+	c.logger.Debug("Got hosts status", zap.Int("hostCount", len(statuses)), zapDebridService)
+	return statuses, nil
+}
+
+// GetHostsRegex fetches and returns the regular expressions of all links that are supported by RealDebrid.
+// The expressions are JavaScript regular expression literals like "/(http|https):\/\/(\w+\.)?1fichier\.com\/\?.*/".
+func (c *Client) GetHostsRegex(ctx context.Context) ([]string, error) {
+	c.logger.Debug("Getting hosts regex...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts/regex", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get hosts regex: %w", err)
+	}
+	regexes := []string{}
+	if err = json.Unmarshal(resBytes, &regexes); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal hosts regex: %w", err)
+	}
+
+	c.logger.Debug("Got hosts regex", zap.Int("regexCount", len(regexes)), zapDebridService)
+	return regexes, nil
+}
+
+// GetHostsDomains fetches and returns the domains of all hosters that are supported by RealDebrid.
+func (c *Client) GetHostsDomains(ctx context.Context) ([]string, error) {
+	c.logger.Debug("Getting hosts domains...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/hosts/domains", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get hosts domains: %w", err)
+	}
+	domains := []string{}
+	if err = json.Unmarshal(resBytes, &domains); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal hosts domains: %w", err)
+	}
+
+	c.logger.Debug("Got hosts domains", zap.Int("domainCount", len(domains)), zapDebridService)
+	return domains, nil
+}
+
+// GetTime fetches and returns the current server time of RealDebrid.
+func (c *Client) GetTime(ctx context.Context) (time.Time, error) {
+	c.logger.Debug("Getting server time...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/time/iso", nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't get server time: %w", err)
+	}
+	// Example: "2020-11-07T13:37:00+0100"
+	t, err := parseDate(strings.TrimSpace(string(resBytes)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't parse server time: %w", err)
+	}
+
+	c.logger.Debug("Got server time", zap.Time("time", t), zapDebridService)
+	return t, nil
+}
@@ -1,10 +1,10 @@
 package realdebrid
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -13,18 +13,23 @@ import (
 	"go.uber.org/zap"
 )
 
-// data can be nil
+// data can be nil. It's sent as query string.
 func (c *Client) get(ctx context.Context, url string, data url.Values) ([]byte, error) {
-	var body io.Reader
+	return c.withToken(ctx, func(token string) ([]byte, error) {
+		return c.doGet(ctx, url, data, token)
+	})
+}
+
+func (c *Client) doGet(ctx context.Context, url string, data url.Values, token string) ([]byte, error) {
 	if len(data) > 0 {
-		body = strings.NewReader(data.Encode())
+		url += "?" + data.Encode()
 	}
-	req, err := http.NewRequest("GET", url, body)
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create GET request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.auth.KeyOrToken)
+	req.Header.Set("Authorization", "Bearer "+token)
 	for headerKey, headerVal := range c.opts.ExtraHeaders {
 		req.Header.Add(headerKey, headerVal)
 	}
@@ -38,8 +43,9 @@ func (c *Client) get(ctx context.Context, url string, data url.Values) ([]byte,
 	resBody, err := ioutil.ReadAll(res.Body)
 	c.logger.Debug("Got response", zap.Int("status", res.StatusCode), zap.NamedError("bodyReadError", err), zap.ByteString("response", resBody), zapDebridService)
 
-	// Check server response status
-	if res.StatusCode != http.StatusOK {
+	// Check server response status.
+	// List endpoints respond with 204 No Content instead of an empty list.
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
 		if err, found := errMap[res.StatusCode]; found {
 			return resBody, err
 		}
@@ -61,11 +67,17 @@ func (c *Client) post(ctx context.Context, url string, data url.Values) ([]byte,
 		}
 		data.Add("ip", c.auth.IP)
 	}
+	return c.withToken(ctx, func(token string) ([]byte, error) {
+		return c.doPost(ctx, url, data, token)
+	})
+}
+
+func (c *Client) doPost(ctx context.Context, url string, data url.Values, token string) ([]byte, error) {
 	req, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create POST request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+c.auth.KeyOrToken)
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	for headerKey, headerVal := range c.opts.ExtraHeaders {
 		req.Header.Add(headerKey, headerVal)
@@ -98,13 +110,68 @@ func (c *Client) post(ctx context.Context, url string, data url.Values) ([]byte,
 	return resBody, nil
 }
 
+func (c *Client) put(ctx context.Context, urlString string, body []byte) ([]byte, error) {
+	// Like for POST requests, RealDebrid asks for the original IP. The body is the uploaded file, so the IP is sent as query string.
+	if c.opts.ForwardOriginIP {
+		if c.auth.IP == "" {
+			return nil, errors.New("auth.IP is empty but client is configured to forward the user's original IP")
+		}
+		urlString += "?" + url.Values{"ip": []string{c.auth.IP}}.Encode()
+	}
+	return c.withToken(ctx, func(token string) ([]byte, error) {
+		return c.doPut(ctx, urlString, body, token)
+	})
+}
+
+func (c *Client) doPut(ctx context.Context, url string, body []byte, token string) ([]byte, error) {
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create PUT request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	for headerKey, headerVal := range c.opts.ExtraHeaders {
+		req.Header.Add(headerKey, headerVal)
+	}
+
+	c.logger.Debug("Sending request", zap.String("request", fmt.Sprintf("%+v", req)), zapDebridService)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't send PUT request: %w", err)
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	c.logger.Debug("Got response", zap.Int("status", res.StatusCode), zap.NamedError("bodyReadError", err), zap.ByteString("response", resBody), zapDebridService)
+
+	// Check server response.
+	if res.StatusCode != http.StatusCreated &&
+		res.StatusCode != http.StatusOK {
+		if err, found := errMap[res.StatusCode]; found {
+			return resBody, err
+		}
+		// resBody can be nil if above ioutil.ReadAll failed, but in that case we don't care about the related error.
+		return resBody, fmt.Errorf("bad HTTP response status: %v", res.Status)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read response body: %w", err)
+	}
+	return resBody, nil
+}
+
 func (c *Client) delete(ctx context.Context, url string) error {
+	_, err := c.withToken(ctx, func(token string) ([]byte, error) {
+		return nil, c.doDelete(ctx, url, token)
+	})
+	return err
+}
+
+func (c *Client) doDelete(ctx context.Context, url string, token string) error {
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
-		return fmt.Errorf("couldn't create GET request: %w", err)
+		return fmt.Errorf("couldn't create DELETE request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.auth.KeyOrToken)
+	req.Header.Set("Authorization", "Bearer "+token)
 	for headerKey, headerVal := range c.opts.ExtraHeaders {
 		req.Header.Add(headerKey, headerVal)
 	}
@@ -118,8 +185,35 @@ func (c *Client) delete(ctx context.Context, url string) error {
 
 	// Check server response status
 	if res.StatusCode != http.StatusNoContent {
+		if err, found := errMap[res.StatusCode]; found {
+			return err
+		}
 		return fmt.Errorf("bad HTTP response status: %v", res.Status)
 	}
 
 	return nil
 }
+
+// withToken calls the request function with the API key or the access token from the token source.
+// With a token source, the request is retried once with a refreshed access token if RealDebrid responds with ErrorBadToken.
+func (c *Client) withToken(ctx context.Context, request func(token string) ([]byte, error)) ([]byte, error) {
+	ts := c.auth.TokenSource
+	if ts == nil {
+		return request(c.auth.KeyOrToken)
+	}
+
+	token, err := ts.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get access token: %w", err)
+	}
+	resBody, err := request(token.AccessToken)
+	if !errors.Is(err, ErrorBadToken) {
+		return resBody, err
+	}
+	c.logger.Debug("Access token was rejected, refreshing it", zapDebridService)
+	token, err = ts.refreshIfCurrent(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't refresh access token: %w", err)
+	}
+	return request(token.AccessToken)
+}
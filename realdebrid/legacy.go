@@ -22,6 +22,8 @@ type LegacyClientOptions struct {
 	Timeout      time.Duration
 	CacheAge     time.Duration
 	ExtraHeaders []string
+	// Selects the file to stream from a torrent with multiple files. Defaults to debrid.DefaultFileSelector.
+	FileSelector debrid.FileSelector
 	// When setting this to true, the user's original IP address is read from Auth.IP and forwarded to RealDebrid for all POST requests.
 	// Only required if the library is used in an app on a machine
 	// whose outgoing IP is different from the machine that's going to request the cached file/stream URL.
@@ -43,6 +45,7 @@ type LegacyClient struct {
 	availabilityCache debrid.Cache
 	cacheAge          time.Duration
 	extraHeaders      map[string]string
+	fileSelector      debrid.FileSelector
 	forwardOriginIP   bool
 	logger            *zap.Logger
 }
@@ -69,6 +72,10 @@ func NewLegacyClient(opts LegacyClientOptions, tokenCache, availabilityCache deb
 		}
 	}
 
+	if opts.FileSelector == nil {
+		opts.FileSelector = debrid.DefaultFileSelector
+	}
+
 	return &LegacyClient{
 		baseURL: opts.BaseURL,
 		httpClient: &http.Client{
@@ -78,6 +85,7 @@ func NewLegacyClient(opts LegacyClientOptions, tokenCache, availabilityCache deb
 		availabilityCache: availabilityCache,
 		cacheAge:          opts.CacheAge,
 		extraHeaders:      extraHeaderMap,
+		fileSelector:      opts.FileSelector,
 		forwardOriginIP:   opts.ForwardOriginIP,
 		logger:            logger,
 	}, nil
@@ -140,6 +148,12 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, auth Auth,
 	requestRequired := false
 	for _, infoHash := range infoHashes {
 		zapFieldInfoHash := zap.String("infoHash", infoHash)
+		// Normalize so that cache keys and results are consistent, no matter how the info_hash was passed
+		infoHash, err := debrid.NormalizeInfoHash(infoHash)
+		if err != nil {
+			c.logger.Error("Couldn't normalize info_hash", zap.Error(err), zapFieldInfoHash, zapFieldDebridSite, zapFieldAPItoken)
+			continue
+		}
 		created, found, err := c.availabilityCache.Get(infoHash)
 		if err != nil {
 			c.logger.Error("Couldn't decode availability cache item", zap.Error(err), zapFieldInfoHash, zapFieldDebridSite, zapFieldAPItoken)
@@ -191,8 +205,11 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, auth Auth,
 				// We don't care about the exact contents for now.
 				// If something was found we can assume the instantly available file of the torrent is the streamable video.
 				if len(value.Get("rd").Array()) > 0 {
-					infoHash := key.String()
-					infoHash = strings.ToUpper(infoHash)
+					infoHash, err := debrid.NormalizeInfoHash(key.String())
+					if err != nil {
+						c.logger.Error("Couldn't normalize info_hash", zap.Error(err), zap.String("infoHash", key.String()), zapFieldDebridSite, zapFieldAPItoken)
+						return true
+					}
 					result = append(result, infoHash)
 					// Create cache item
 					if err = c.availabilityCache.Set(infoHash); err != nil {
@@ -207,6 +224,12 @@ func (c *LegacyClient) CheckInstantAvailability(ctx context.Context, auth Auth,
 }
 
 func (c *LegacyClient) GetStreamURL(ctx context.Context, magnetURL string, auth Auth, remote bool) (string, error) {
+	return c.GetStreamURLWithSelector(ctx, magnetURL, auth, remote, c.fileSelector)
+}
+
+// GetStreamURLWithSelector is like GetStreamURL, but uses the given file selector instead of the one from the client options.
+// This is useful for selecting a specific episode from a season pack, see debrid.EpisodeSelector.
+func (c *LegacyClient) GetStreamURLWithSelector(ctx context.Context, magnetURL string, auth Auth, remote bool, selector debrid.FileSelector) (string, error) {
 	zapFieldDebridSite := zap.String("debridSite", "RealDebrid")
 	zapFieldAPItoken := zap.String("keyOrToken", auth.KeyOrToken)
 	c.logger.Debug("Adding torrent to RealDebrid...", zapFieldDebridSite, zapFieldAPItoken)
@@ -239,8 +262,9 @@ func (c *LegacyClient) GetStreamURL(ctx context.Context, magnetURL string, auth
 	if len(fileResults) == 0 || (len(fileResults) == 1 && fileResults[0].Raw == "") {
 		return "", errors.New("Couldn't get torrent info from real-debrid.com: response body doesn't contain \"files\" key")
 	}
-	// TODO: Not required if we pass the instant available file ID from the availability check, but probably no huge performance implication
-	fileID, err := selectFileID(ctx, fileResults)
+	// Note: The legacy availability check doesn't keep the instantly available file IDs, so this might select a file that's not cached.
+	// Client.GetInstantDownload selects exactly the cached files and fails fast instead.
+	fileID, err := selectFileID(ctx, fileResults, selector)
 	if err != nil {
 		return "", fmt.Errorf("Couldn't find proper file in torrent: %v", err)
 	}
@@ -405,21 +429,22 @@ func (c *LegacyClient) post(ctx context.Context, url string, auth Auth, data url
 	return ioutil.ReadAll(res.Body)
 }
 
-func selectFileID(ctx context.Context, fileResults []gjson.Result) (string, error) {
+func selectFileID(ctx context.Context, fileResults []gjson.Result, selector debrid.FileSelector) (string, error) {
 	// Precondition check
 	if len(fileResults) == 0 {
 		return "", fmt.Errorf("Empty slice of files")
 	}
 
-	var fileID int64 // ID inside JSON starts with 1
-	var size int64
-	for _, res := range fileResults {
-		if res.Get("bytes").Int() > size {
-			size = res.Get("bytes").Int()
-			fileID = res.Get("id").Int()
-		}
+	files := make([]debrid.File, len(fileResults))
+	for i, res := range fileResults {
+		files[i] = debrid.File{Path: res.Get("path").String(), Size: res.Get("bytes").Int()}
+	}
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return "", err
 	}
 
+	fileID := fileResults[i].Get("id").Int() // ID inside JSON starts with 1
 	if fileID == 0 {
 		return "", fmt.Errorf("No file ID found")
 	}
@@ -0,0 +1,317 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrorAuthorizationPending is returned by OAuth2Client.GetCredentials when the user hasn't entered the user code yet.
+var ErrorAuthorizationPending = errors.New("authorization pending")
+
+// ErrorDeviceCodeExpired is returned by OAuth2Client.WaitForToken when the user didn't enter the user code before the device code expired.
+var ErrorDeviceCodeExpired = errors.New("device code expired")
+
+// deviceGrantType is the grant type RealDebrid uses for both getting and refreshing tokens in the device code flow.
+const deviceGrantType = "http://oauth.net/grant_type/device/1.0"
+
+// OAuth2Options are options for the OAuth2 client.
+type OAuth2Options struct {
+	// Base URL for HTTP requests
+	BaseURL string
+	// Timeout for HTTP requests
+	Timeout time.Duration
+	// Client ID of the app. The default one is RealDebrid's client ID for open source apps.
+	ClientID string
+}
+
+// DefaultOAuth2Opts are OAuth2Options with reasonable default values.
+var DefaultOAuth2Opts = OAuth2Options{
+	BaseURL:  "https://api.real-debrid.com/oauth/v2",
+	Timeout:  5 * time.Second,
+	ClientID: "X245A4XAIBGVM",
+}
+
+// OAuth2Client is a client for RealDebrid's OAuth2 device code flow.
+// The flow is:
+//  1. GetDeviceCode and show DeviceCode.UserCode and DeviceCode.VerificationURL to the user
+//  2. WaitForToken while the user enters the user code on the verification URL
+//  3. Store the Credentials and Token and use them for a TokenSource
+type OAuth2Client struct {
+	opts       OAuth2Options
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewOAuth2Client returns a new RealDebrid OAuth2 client.
+// The logger param can be nil.
+func NewOAuth2Client(opts OAuth2Options, logger *zap.Logger) *OAuth2Client {
+	// Set default values
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultOAuth2Opts.BaseURL
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultOAuth2Opts.Timeout
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = DefaultOAuth2Opts.ClientID
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &OAuth2Client{
+		opts: opts,
+		httpClient: &http.Client{
+			Timeout: opts.Timeout,
+		},
+		logger: logger,
+	}
+}
+
+// GetDeviceCode starts the device code flow and returns the codes for the user and for polling.
+// It requests new user-specific client credentials, which are required for refreshing tokens.
+func (c *OAuth2Client) GetDeviceCode(ctx context.Context) (DeviceCode, error) {
+	c.logger.Debug("Getting device code...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("client_id", c.opts.ClientID)
+	data.Set("new_credentials", "yes")
+	resBytes, err := c.do(ctx, "GET", c.opts.BaseURL+"/device/code?"+data.Encode(), nil)
+	if err != nil {
+		return DeviceCode{}, fmt.Errorf("couldn't get device code: %w", err)
+	}
+	code := DeviceCode{}
+	if err = json.Unmarshal(resBytes, &code); err != nil {
+		return DeviceCode{}, fmt.Errorf("couldn't unmarshal device code: %w", err)
+	}
+	code.Expiry = time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	c.logger.Debug("Got device code", zap.String("userCode", code.UserCode), zapDebridService)
+	return code, nil
+}
+
+// GetCredentials returns the user-specific client credentials once the user entered the user code.
+// Until then ErrorAuthorizationPending is returned.
+func (c *OAuth2Client) GetCredentials(ctx context.Context, deviceCode string) (Credentials, error) {
+	c.logger.Debug("Getting credentials...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("client_id", c.opts.ClientID)
+	data.Set("code", deviceCode)
+	resBytes, err := c.do(ctx, "GET", c.opts.BaseURL+"/device/credentials?"+data.Encode(), nil)
+	if errors.Is(err, ErrorPermissionDenied) {
+		return Credentials{}, ErrorAuthorizationPending
+	} else if err != nil {
+		return Credentials{}, fmt.Errorf("couldn't get credentials: %w", err)
+	}
+	creds := Credentials{}
+	if err = json.Unmarshal(resBytes, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("couldn't unmarshal credentials: %w", err)
+	}
+	if creds.ClientID == "" {
+		return Credentials{}, ErrorAuthorizationPending
+	}
+
+	c.logger.Debug("Got credentials", zapDebridService)
+	return creds, nil
+}
+
+// GetToken returns a new token for the credentials and device code.
+func (c *OAuth2Client) GetToken(ctx context.Context, creds Credentials, deviceCode string) (Token, error) {
+	c.logger.Debug("Getting token...", zapDebridService)
+
+	token, err := c.requestToken(ctx, creds, deviceCode)
+	if err != nil {
+		return Token{}, fmt.Errorf("couldn't get token: %w", err)
+	}
+
+	c.logger.Debug("Got token", zap.Time("expiry", token.Expiry), zapDebridService)
+	return token, nil
+}
+
+// RefreshToken returns a new token for the credentials and refresh token.
+func (c *OAuth2Client) RefreshToken(ctx context.Context, creds Credentials, refreshToken string) (Token, error) {
+	c.logger.Debug("Refreshing token...", zapDebridService)
+
+	token, err := c.requestToken(ctx, creds, refreshToken)
+	if err != nil {
+		return Token{}, fmt.Errorf("couldn't refresh token: %w", err)
+	}
+	// Keep using the old refresh token if RealDebrid doesn't send a new one
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	c.logger.Debug("Refreshed token", zap.Time("expiry", token.Expiry), zapDebridService)
+	return token, nil
+}
+
+// WaitForToken polls RealDebrid in the interval of the device code until the user entered the user code,
+// and then returns the user-specific credentials and a token.
+// It returns ErrorDeviceCodeExpired when the device code expires, or the context's error when the context is done.
+func (c *OAuth2Client) WaitForToken(ctx context.Context, code DeviceCode) (Credentials, Token, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		creds, err := c.GetCredentials(ctx, code.DeviceCode)
+		if err == nil {
+			token, err := c.GetToken(ctx, creds, code.DeviceCode)
+			return creds, token, err
+		} else if !errors.Is(err, ErrorAuthorizationPending) {
+			return Credentials{}, Token{}, err
+		}
+		if !code.Expiry.IsZero() && time.Now().After(code.Expiry) {
+			return Credentials{}, Token{}, ErrorDeviceCodeExpired
+		}
+
+		select {
+		case <-ctx.Done():
+			return Credentials{}, Token{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *OAuth2Client) requestToken(ctx context.Context, creds Credentials, code string) (Token, error) {
+	data := url.Values{}
+	data.Set("client_id", creds.ClientID)
+	data.Set("client_secret", creds.ClientSecret)
+	data.Set("code", code)
+	data.Set("grant_type", deviceGrantType)
+	resBytes, err := c.do(ctx, "POST", c.opts.BaseURL+"/token", data)
+	if err != nil {
+		return Token{}, err
+	}
+	token := Token{}
+	if err = json.Unmarshal(resBytes, &token); err != nil {
+		return Token{}, fmt.Errorf("couldn't unmarshal token: %w", err)
+	}
+	token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return token, nil
+}
+
+// data can be nil. It's sent as form body.
+func (c *OAuth2Client) do(ctx context.Context, method, url string, data url.Values) ([]byte, error) {
+	var req *http.Request
+	var err error
+	if data != nil {
+		req, err = http.NewRequest(method, url, strings.NewReader(data.Encode()))
+	} else {
+		req, err = http.NewRequest(method, url, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create %v request: %w", method, err)
+	}
+	req = req.WithContext(ctx)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't send %v request: %w", method, err)
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	// Don't log the response body, it contains secrets
+	c.logger.Debug("Got response", zap.Int("status", res.StatusCode), zap.NamedError("bodyReadError", err), zapDebridService)
+
+	// Check server response status
+	if res.StatusCode != http.StatusOK {
+		if err, found := errMap[res.StatusCode]; found {
+			return resBody, err
+		}
+		// resBody can be nil if above ioutil.ReadAll failed, but in that case we don't care about the related error.
+		return resBody, fmt.Errorf("bad HTTP response status: %v", res.Status)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read response body: %w", err)
+	}
+	return resBody, nil
+}
+
+// TokenSource provides OAuth2 access tokens for a Client and refreshes them when they're about to expire.
+// It's safe for concurrent use.
+// Set it as Auth.TokenSource to make a Client use it.
+type TokenSource struct {
+	oauth2Client *OAuth2Client
+	creds        Credentials
+	// Access tokens are refreshed when they expire within this duration
+	expiryDelta time.Duration
+	onRefresh   func(Token)
+
+	lock  sync.Mutex
+	token Token
+}
+
+// NewTokenSource returns a new TokenSource for the credentials and the token from OAuth2Client.WaitForToken.
+// The token can also be a stored one, as long as its refresh token is still valid.
+// The onRefresh param can be nil. If set, it's called with each new token, for example to store it.
+func NewTokenSource(oauth2Client *OAuth2Client, creds Credentials, token Token, onRefresh func(Token)) *TokenSource {
+	return &TokenSource{
+		oauth2Client: oauth2Client,
+		creds:        creds,
+		expiryDelta:  time.Minute,
+		onRefresh:    onRefresh,
+		token:        token,
+	}
+}
+
+// Token returns a valid token, refreshing it first if it expires soon.
+func (ts *TokenSource) Token(ctx context.Context) (Token, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if time.Now().Add(ts.expiryDelta).Before(ts.token.Expiry) {
+		return ts.token, nil
+	}
+	return ts.refresh(ctx)
+}
+
+// Refresh refreshes the token regardless of its expiry.
+func (ts *TokenSource) Refresh(ctx context.Context) (Token, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return ts.refresh(ctx)
+}
+
+// refreshIfCurrent refreshes the token, unless the given access token was already replaced by another goroutine.
+// This prevents multiple refreshes when concurrent requests fail due to the same expired access token.
+func (ts *TokenSource) refreshIfCurrent(ctx context.Context, accessToken string) (Token, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.token.AccessToken != accessToken {
+		return ts.token, nil
+	}
+	return ts.refresh(ctx)
+}
+
+// Must only be called while holding the lock.
+func (ts *TokenSource) refresh(ctx context.Context) (Token, error) {
+	token, err := ts.oauth2Client.RefreshToken(ctx, ts.creds, ts.token.RefreshToken)
+	if err != nil {
+		return Token{}, err
+	}
+	ts.token = token
+	if ts.onRefresh != nil {
+		ts.onRefresh(token)
+	}
+	return token, nil
+}
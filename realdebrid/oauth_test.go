@@ -0,0 +1,133 @@
+package realdebrid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/realdebrid"
+)
+
+func TestDeviceCodeFlow(t *testing.T) {
+	var credentialsPolls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/device/code":
+			assert.Equal(t, "yes", r.URL.Query().Get("new_credentials"))
+			_, _ = w.Write([]byte(`{"device_code":"DEVICE","user_code":"USER","interval":1,"expires_in":600,"verification_url":"https://real-debrid.com/device"}`))
+		case "/device/credentials":
+			assert.Equal(t, "DEVICE", r.URL.Query().Get("code"))
+			// The user enters the code after the first poll
+			if atomic.AddInt32(&credentialsPolls, 1) == 1 {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"client_id":"ID","client_secret":"SECRET"}`))
+		case "/token":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "ID", r.PostForm.Get("client_id"))
+			assert.Equal(t, "SECRET", r.PostForm.Get("client_secret"))
+			assert.Equal(t, "http://oauth.net/grant_type/device/1.0", r.PostForm.Get("grant_type"))
+			if r.PostForm.Get("code") == "DEVICE" {
+				_, _ = w.Write([]byte(`{"access_token":"ACCESS1","expires_in":3600,"token_type":"Bearer","refresh_token":"REFRESH"}`))
+			} else {
+				assert.Equal(t, "REFRESH", r.PostForm.Get("code"))
+				_, _ = w.Write([]byte(`{"access_token":"ACCESS2","expires_in":3600,"token_type":"Bearer"}`))
+			}
+		case "/user":
+			// The first access token is revoked
+			if r.Header.Get("Authorization") != "Bearer ACCESS2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":123,"username":"foo"}`))
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oauth2Opts := realdebrid.DefaultOAuth2Opts
+	oauth2Opts.BaseURL = server.URL
+	oauth2Client := realdebrid.NewOAuth2Client(oauth2Opts, nil)
+
+	code, err := oauth2Client.GetDeviceCode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "USER", code.UserCode)
+	require.WithinDuration(t, time.Now().Add(600*time.Second), code.Expiry, time.Minute)
+
+	_, err = oauth2Client.GetCredentials(ctx, code.DeviceCode)
+	require.ErrorIs(t, err, realdebrid.ErrorAuthorizationPending)
+
+	creds, token, err := oauth2Client.WaitForToken(ctx, code)
+	require.NoError(t, err)
+	require.Equal(t, realdebrid.Credentials{ClientID: "ID", ClientSecret: "SECRET"}, creds)
+	require.Equal(t, "ACCESS1", token.AccessToken)
+	require.Equal(t, "REFRESH", token.RefreshToken)
+
+	// The client picks up the refreshed token after the first one is rejected
+	var refreshed []realdebrid.Token
+	ts := realdebrid.NewTokenSource(oauth2Client, creds, token, func(token realdebrid.Token) {
+		refreshed = append(refreshed, token)
+	})
+	clientOpts := realdebrid.DefaultClientOpts
+	clientOpts.BaseURL = server.URL
+	client := realdebrid.NewClient(clientOpts, realdebrid.Auth{TokenSource: ts}, nil)
+	user, err := client.GetUser(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "foo", user.Username)
+	require.Len(t, refreshed, 1)
+	require.Equal(t, "ACCESS2", refreshed[0].AccessToken)
+	// The old refresh token is kept
+	require.Equal(t, "REFRESH", refreshed[0].RefreshToken)
+
+	token, err = ts.Token(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ACCESS2", token.AccessToken)
+}
+
+func TestTokenSourceExpiry(t *testing.T) {
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+		_, _ = w.Write([]byte(`{"access_token":"NEW","expires_in":3600,"token_type":"Bearer","refresh_token":"REFRESH2"}`))
+	}))
+	defer server.Close()
+
+	oauth2Opts := realdebrid.DefaultOAuth2Opts
+	oauth2Opts.BaseURL = server.URL
+	oauth2Client := realdebrid.NewOAuth2Client(oauth2Opts, nil)
+	ctx := context.Background()
+
+	// Valid token isn't refreshed
+	ts := realdebrid.NewTokenSource(oauth2Client, realdebrid.Credentials{}, realdebrid.Token{AccessToken: "OLD", Expiry: time.Now().Add(time.Hour)}, nil)
+	token, err := ts.Token(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "OLD", token.AccessToken)
+	require.Equal(t, int32(0), atomic.LoadInt32(&refreshes))
+
+	// Token that expires soon is refreshed, only once for concurrent calls
+	ts = realdebrid.NewTokenSource(oauth2Client, realdebrid.Credentials{}, realdebrid.Token{AccessToken: "OLD", Expiry: time.Now().Add(time.Second)}, nil)
+	done := make(chan realdebrid.Token)
+	for i := 0; i < 5; i++ {
+		go func() {
+			token, err := ts.Token(ctx)
+			assert.NoError(t, err)
+			done <- token
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		require.Equal(t, "NEW", (<-done).AccessToken)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+}
@@ -0,0 +1,57 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap"
+)
+
+// GetSettings fetches and returns the user's settings.
+func (c *Client) GetSettings(ctx context.Context) (Settings, error) {
+	c.logger.Debug("Getting settings...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/settings", nil)
+	if err != nil {
+		return Settings{}, fmt.Errorf("couldn't get settings: %w", err)
+	}
+	settings := Settings{}
+	if err = json.Unmarshal(resBytes, &settings); err != nil {
+		return Settings{}, fmt.Errorf("couldn't unmarshal settings: %w", err)
+	}
+
+	c.logger.Debug("Got settings", zap.String("settings", fmt.Sprintf("%+v", settings)), zapDebridService)
+	return settings, nil
+}
+
+// UpdateSetting updates one of the user's settings.
+// Valid names are "download_port", "locale", "streaming_language_preference", "streaming_quality",
+// "mobile_streaming_quality" and "streaming_cast_audio_preference".
+// Valid values are listed in the Settings that GetSettings returns.
+func (c *Client) UpdateSetting(ctx context.Context, name, value string) error {
+	c.logger.Debug("Updating setting...", zap.String("name", name), zapDebridService)
+
+	data := url.Values{}
+	data.Set("setting_name", name)
+	data.Set("setting_value", value)
+	if _, err := c.post(ctx, c.opts.BaseURL+"/settings/update", data); err != nil {
+		return fmt.Errorf("couldn't update setting: %w", err)
+	}
+
+	c.logger.Debug("Updated setting", zap.String("name", name), zapDebridService)
+	return nil
+}
+
+// ConvertPoints converts the user's fidelity points to premium days.
+func (c *Client) ConvertPoints(ctx context.Context) error {
+	c.logger.Debug("Converting points...", zapDebridService)
+
+	if _, err := c.post(ctx, c.opts.BaseURL+"/settings/convertPoints", url.Values{}); err != nil {
+		return fmt.Errorf("couldn't convert points: %w", err)
+	}
+
+	c.logger.Debug("Converted points", zapDebridService)
+	return nil
+}
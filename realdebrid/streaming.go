@@ -0,0 +1,45 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// GetTranscodeLinks fetches and returns the links to transcoded streams of an unrestricted file.
+// The ID must be the Download.ID from unrestricting a link.
+func (c *Client) GetTranscodeLinks(ctx context.Context, id string) (TranscodeLinks, error) {
+	c.logger.Debug("Getting transcode links...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/streaming/transcode/"+id, nil)
+	if err != nil {
+		return TranscodeLinks{}, fmt.Errorf("couldn't get transcode links: %w", err)
+	}
+	links := TranscodeLinks{}
+	if err = json.Unmarshal(resBytes, &links); err != nil {
+		return TranscodeLinks{}, fmt.Errorf("couldn't unmarshal transcode links: %w", err)
+	}
+
+	c.logger.Debug("Got transcode links", zap.String("transcodeLinks", fmt.Sprintf("%+v", links)), zapDebridService)
+	return links, nil
+}
+
+// GetMediaInfos fetches and returns info about the media of an unrestricted file, like its duration and its video, audio and subtitle tracks.
+// The ID must be the Download.ID from unrestricting a link.
+func (c *Client) GetMediaInfos(ctx context.Context, id string) (MediaInfos, error) {
+	c.logger.Debug("Getting media infos...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/streaming/mediaInfos/"+id, nil)
+	if err != nil {
+		return MediaInfos{}, fmt.Errorf("couldn't get media infos: %w", err)
+	}
+	infos := MediaInfos{}
+	if err = json.Unmarshal(resBytes, &infos); err != nil {
+		return MediaInfos{}, fmt.Errorf("couldn't unmarshal media infos: %w", err)
+	}
+
+	c.logger.Debug("Got media infos", zap.String("mediaInfos", fmt.Sprintf("%+v", infos)), zapDebridService)
+	return infos, nil
+}
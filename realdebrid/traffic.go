@@ -0,0 +1,54 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetTraffic fetches and returns the user's remaining traffic for limited hosters, mapped by their domain.
+func (c *Client) GetTraffic(ctx context.Context) (map[string]Traffic, error) {
+	c.logger.Debug("Getting traffic...", zapDebridService)
+
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/traffic", nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get traffic: %w", err)
+	}
+	traffic := map[string]Traffic{}
+	if err = json.Unmarshal(resBytes, &traffic); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal traffic: %w", err)
+	}
+
+	c.logger.Debug("Got traffic", zap.String("traffic", fmt.Sprintf("%+v", traffic)), zapDebridService)
+	return traffic, nil
+}
+
+// GetTrafficDetails fetches and returns the user's downloaded traffic per day, mapped by the date in the "YYYY-MM-DD" format.
+// Start and end are optional and can be zero values. RealDebrid then defaults to the last week.
+// The period can't be longer than 31 days.
+func (c *Client) GetTrafficDetails(ctx context.Context, start, end time.Time) (map[string]TrafficDetails, error) {
+	c.logger.Debug("Getting traffic details...", zapDebridService)
+
+	data := url.Values{}
+	if !start.IsZero() {
+		data.Set("start", start.Format("2006-01-02"))
+	}
+	if !end.IsZero() {
+		data.Set("end", end.Format("2006-01-02"))
+	}
+	resBytes, err := c.get(ctx, c.opts.BaseURL+"/traffic/details", data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get traffic details: %w", err)
+	}
+	details := map[string]TrafficDetails{}
+	if err = json.Unmarshal(resBytes, &details); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal traffic details: %w", err)
+	}
+
+	c.logger.Debug("Got traffic details", zap.String("details", fmt.Sprintf("%+v", details)), zapDebridService)
+	return details, nil
+}
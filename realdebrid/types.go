@@ -1,8 +1,15 @@
 package realdebrid
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 	"time"
+
+	"github.com/deflix-tv/go-debrid/internal/jsonutil"
 )
 
 var (
@@ -21,6 +28,8 @@ var (
 	// Service unavailable.
 	// Corresponds to RealDebrid 503 status code.
 	ErrorServiceUnavailable = errors.New("service unavailable")
+	// Returned when RealDebrid would have to download a torrent before it can be streamed.
+	ErrorNotInstantlyAvailable = errors.New("not instantly available")
 )
 
 var errMap = map[int]error{
@@ -43,9 +52,35 @@ type User struct {
 	Avatar string `json:"avatar,omitempty"`
 	// "premium" or "free"
 	Type string `json:"type,omitempty"`
-	// seconds left as a Premium user
-	Premium    int       `json:"premium,omitempty"`
-	Expiration time.Time `json:"expiration,omitempty"`
+	// Time left as a Premium user
+	Premium    time.Duration `json:"premium,omitempty"`
+	Expiration time.Time     `json:"expiration,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the seconds left as a Premium user and the expiration date.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	raw := struct {
+		*user
+		Premium    jsonutil.Seconds `json:"premium,omitempty"`
+		Expiration jsonDate         `json:"expiration,omitempty"`
+	}{user: (*user)(u)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	u.Premium = time.Duration(raw.Premium)
+	u.Expiration = time.Time(raw.Expiration)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return json.Marshal(struct {
+		user
+		Premium jsonutil.Seconds `json:"premium"`
+	}{user: user(u), Premium: jsonutil.Seconds(u.Premium)})
 }
 
 // Download represents an unrestricted link.
@@ -55,7 +90,7 @@ type Download struct {
 	// Mime Type of the file, guessed by the file extension
 	MimeType string `json:"mimeType,omitempty"`
 	// Filesize in bytes, 0 if unknown
-	Filesize int `json:"filesize,omitempty"`
+	Filesize int64 `json:"filesize,omitempty"`
 	// Original link
 	Link string `json:"link,omitempty"`
 	// Host main domain
@@ -68,6 +103,36 @@ type Download struct {
 	Download string `json:"download,omitempty"`
 	// Is the file streamable on website
 	Streamable int `json:"streamable,omitempty"`
+	// !! Only present in the downloads list
+	Generated time.Time `json:"generated,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the generation date.
+func (d *Download) UnmarshalJSON(data []byte) error {
+	type download Download
+	raw := struct {
+		*download
+		Generated jsonDate `json:"generated,omitempty"`
+	}{download: (*download)(d)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	d.Generated = time.Time(raw.Generated)
+	return nil
+}
+
+// LinkCheck contains info about a hoster link, as returned by checking the link without unrestricting it.
+type LinkCheck struct {
+	// Host main domain
+	Host string `json:"host,omitempty"`
+	// Original link
+	Link     string `json:"link,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	// Filesize in bytes, 0 if unknown
+	Filesize int64 `json:"filesize,omitempty"`
+	// 0 or 1
+	Supported int `json:"supported,omitempty"`
 }
 
 // TorrentsInfo contains info about one element of a list of torrents that was added to RealDebrid for a specific user.
@@ -79,7 +144,7 @@ type TorrentsInfo struct {
 	// SHA1 Hash of the torrent
 	Hash string `json:"hash,omitempty"`
 	// Size of selected files only
-	Bytes int `json:"bytes,omitempty"`
+	Bytes int64 `json:"bytes,omitempty"`
 	// Host main domain
 	Host string `json:"host,omitempty"`
 	// Split size of links
@@ -91,14 +156,31 @@ type TorrentsInfo struct {
 	Added  time.Time `json:"added,omitempty"`
 	// Host URLs
 	Links []string `json:"links,omitempty"`
-	// !! Only present when finished, jsonDate
-	Ended string `json:"ended,omitempty"`
-	// !! Only present in "downloading", "compressing", "uploading" status
-	Speed int `json:"speed,omitempty"`
+	// !! Only present when finished
+	Ended time.Time `json:"ended,omitempty"`
+	// !! Only present in "downloading", "compressing", "uploading" status. In bytes per second.
+	Speed int64 `json:"speed,omitempty"`
 	// !! Only present in "downloading", "magnet_conversion" status
 	Seeders int `json:"seeders,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the dates, which are empty while the torrent isn't finished.
+func (i *TorrentsInfo) UnmarshalJSON(data []byte) error {
+	type torrentsInfo TorrentsInfo
+	raw := struct {
+		*torrentsInfo
+		Added jsonDate `json:"added,omitempty"`
+		Ended jsonDate `json:"ended,omitempty"`
+	}{torrentsInfo: (*torrentsInfo)(i)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.Added = time.Time(raw.Added)
+	i.Ended = time.Time(raw.Ended)
+	return nil
+}
+
 // TorrentInfo contains info about a specific torrent that was added to RealDebrid for a specific user.
 // It contains download info (progress, selected files) after one or more files of the torrent were selected to be downloaded.
 // It's similar to TorrentsInfo, but has some additional fields like OriginalFilename, OriginalBytes and Files.
@@ -110,9 +192,9 @@ type TorrentInfo struct {
 	// SHA1 Hash of the torrent
 	Hash string `json:"hash,omitempty"`
 	// Size of selected files only
-	Bytes int `json:"bytes,omitempty"`
+	Bytes int64 `json:"bytes,omitempty"`
 	// Total size of the torrent
-	OriginalBytes int `json:"original_bytes,omitempty"`
+	OriginalBytes int64 `json:"original_bytes,omitempty"`
 	// Host main domain
 	Host string `json:"host,omitempty"`
 	// Split size of links
@@ -125,29 +207,431 @@ type TorrentInfo struct {
 	Files  []File    `json:"files,omitempty"`
 	// Host URLs
 	Links []string `json:"links,omitempty"`
-	// !! Only present when finished, jsonDate
-	Ended string `json:"ended,omitempty"`
-	// !! Only present in "downloading", "compressing", "uploading" status
-	Speed int `json:"speed,omitempty"`
+	// !! Only present when finished
+	Ended time.Time `json:"ended,omitempty"`
+	// !! Only present in "downloading", "compressing", "uploading" status. In bytes per second.
+	Speed int64 `json:"speed,omitempty"`
 	// !! Only present in "downloading", "magnet_conversion" status
 	Seeders int `json:"seeders,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the dates, which are empty while the torrent isn't finished.
+func (i *TorrentInfo) UnmarshalJSON(data []byte) error {
+	type torrentInfo TorrentInfo
+	raw := struct {
+		*torrentInfo
+		Added jsonDate `json:"added,omitempty"`
+		Ended jsonDate `json:"ended,omitempty"`
+	}{torrentInfo: (*torrentInfo)(i)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.Added = time.Time(raw.Added)
+	i.Ended = time.Time(raw.Ended)
+	return nil
+}
+
 // File represents a file in a torrent.
 type File struct {
 	ID int `json:"id,omitempty"`
 	// Path to the file inside the torrent, starting with "/"
 	Path  string `json:"path,omitempty"`
-	Bytes int    `json:"bytes,omitempty"`
+	Bytes int64  `json:"bytes,omitempty"`
 	// 0 or 1
 	Selected int `json:"selected,omitempty"`
 }
 
 // InstantAvailability maps torrent file IDs to their availability.
+// With RealDebrid's variants, an InstantAvailability is one set of files that are cached together.
 type InstantAvailability map[int]AvailableFile
 
+// FileIDs returns the sorted file IDs, for example to pass them to Client.SelectFiles.
+func (a InstantAvailability) FileIDs() []int {
+	ids := make([]int, 0, len(a))
+	for id := range a {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 // AvailableFile represents an instantly available file.
 type AvailableFile struct {
 	Filename string `json:"filename,omitempty"`
-	Filesize int    `json:"filesize,omitempty"`
+	Filesize int64  `json:"filesize,omitempty"`
+}
+
+// InstantDownload is the result of resolving an instantly available torrent file to a direct download.
+type InstantDownload struct {
+	// ID of the torrent that was added to the user's torrents. Can be used to delete the torrent later.
+	TorrentID string
+	// ID of the selected file in the torrent
+	FileID int
+	// The unrestricted link of the selected file
+	Download Download
+}
+
+// Host represents a hoster that's supported by RealDebrid.
+type Host struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Image    string `json:"image,omitempty"`
+	ImageBig string `json:"image_big,omitempty"`
+}
+
+// HostStatus contains the status of a hoster on RealDebrid and on competing debrid services.
+type HostStatus struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Image    string `json:"image,omitempty"`
+	ImageBig string `json:"image_big,omitempty"`
+	// 0 or 1
+	Supported int `json:"supported,omitempty"`
+	// "up", "down" or "unsupported"
+	Status    string    `json:"status,omitempty"`
+	CheckTime time.Time `json:"check_time,omitempty"`
+	// Maps the competitors' domains to their status
+	CompetitorsStatus map[string]CompetitorStatus `json:"competitors_status,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the check time.
+func (s *HostStatus) UnmarshalJSON(data []byte) error {
+	type hostStatus HostStatus
+	raw := struct {
+		*hostStatus
+		CheckTime jsonDate `json:"check_time,omitempty"`
+	}{hostStatus: (*hostStatus)(s)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	s.CheckTime = time.Time(raw.CheckTime)
+	return nil
+}
+
+// CompetitorStatus contains the status of a hoster on a debrid service competing with RealDebrid.
+type CompetitorStatus struct {
+	// "up", "down" or "unsupported"
+	Status    string    `json:"status,omitempty"`
+	CheckTime time.Time `json:"check_time,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the check time.
+func (s *CompetitorStatus) UnmarshalJSON(data []byte) error {
+	type competitorStatus CompetitorStatus
+	raw := struct {
+		*competitorStatus
+		CheckTime jsonDate `json:"check_time,omitempty"`
+	}{competitorStatus: (*competitorStatus)(s)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	s.CheckTime = time.Time(raw.CheckTime)
+	return nil
+}
+
+// Traffic contains info about the user's remaining traffic for a limited hoster.
+type Traffic struct {
+	// Available bytes / links to use
+	Left int64 `json:"left,omitempty"`
+	// Bytes downloaded
+	Bytes int64 `json:"bytes,omitempty"`
+	// Links unrestricted
+	Links int `json:"links,omitempty"`
+	// Limit of the hoster
+	Limit int64 `json:"limit,omitempty"`
+	// Type of the limit: "links", "gigabytes" or "bytes"
+	Type string `json:"type,omitempty"`
+	// Additional traffic / links the user may have bought
+	Extra int64 `json:"extra,omitempty"`
+	// Reset of the limit: "daily", "weekly" or "monthly"
+	Reset string `json:"reset,omitempty"`
+}
+
+// TrafficDetails contains the traffic the user downloaded on one day.
+type TrafficDetails struct {
+	// Maps the hosters' domains to the bytes downloaded from them
+	Host map[string]int64 `json:"host,omitempty"`
+	// Total downloaded (in bytes) this day
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+// Settings contains the user's settings.
+type Settings struct {
+	// Possible "download_port" values to update settings
+	DownloadPorts []string `json:"download_ports,omitempty"`
+	// Current user download port: "normal" or "secured"
+	DownloadPort string `json:"download_port,omitempty"`
+	// Possible "locale" values to update settings, mapped to their display name
+	Locales map[string]string `json:"locales,omitempty"`
+	// Current user locale
+	Locale string `json:"locale,omitempty"`
+	// Possible "streaming_quality" values to update settings
+	StreamingQualities []string `json:"streaming_qualities,omitempty"`
+	// Current user streaming quality
+	StreamingQuality string `json:"streaming_quality,omitempty"`
+	// Current user streaming quality on mobile devices
+	MobileStreamingQuality string `json:"mobile_streaming_quality,omitempty"`
+	// Possible "streaming_language_preference" values to update settings, mapped to their display name
+	StreamingLanguages map[string]string `json:"streaming_languages,omitempty"`
+	// Current user streaming language preference
+	StreamingLanguagePreference string `json:"streaming_language_preference,omitempty"`
+	// Possible "streaming_cast_audio_preference" values to update settings, mapped to their display name
+	StreamingCastAudio map[string]string `json:"streaming_cast_audio,omitempty"`
+	// Current user audio preference on Google Cast devices
+	StreamingCastAudioPreference string `json:"streaming_cast_audio_preference,omitempty"`
+}
+
+// ActiveCount contains the number of currently active torrents and the maximum number of active torrents the user can have.
+type ActiveCount struct {
+	// Number of currently active torrents
+	Count int `json:"nb,omitempty"`
+	// Maximum number of active torrents
+	Limit int `json:"limit,omitempty"`
+}
+
+// AvailableHost is a host that a torrent can be downloaded to.
+type AvailableHost struct {
+	Host string `json:"host,omitempty"`
+	// Max split size possible on this host, in GB
+	MaxFileSize int `json:"max_file_size,omitempty"`
+}
+
+// TranscodeLinks contains the links to transcoded streams of an unrestricted file, mapped by quality (like "original" or "1080p").
+type TranscodeLinks struct {
+	// HLS streams (M3U8)
+	HLS map[string]string `json:"apple,omitempty"`
+	// MPEG-DASH streams (MPD)
+	DASH map[string]string `json:"dash,omitempty"`
+	// Live transcoded MP4 streams
+	LiveMP4 map[string]string `json:"liveMP4,omitempty"`
+	// Live transcoded H264 WebM streams
+	H264WebM map[string]string `json:"h264WebM,omitempty"`
+}
+
+// MediaInfos contains info about the media of an unrestricted file.
+type MediaInfos struct {
+	Filename string `json:"filename,omitempty"`
+	Hoster   string `json:"hoster,omitempty"`
+	// Original link
+	Link string `json:"link,omitempty"`
+	// "movie", "show" or "audio"
+	Type string `json:"type,omitempty"`
+	// !! Only present for shows
+	Season string `json:"season,omitempty"`
+	// !! Only present for shows
+	Episode string `json:"episode,omitempty"`
+	Year    string `json:"year,omitempty"`
+	// Media duration
+	Duration time.Duration `json:"duration,omitempty"`
+	// Bitrate of the media file
+	Bitrate int `json:"bitrate,omitempty"`
+	// Original filesize in bytes
+	Size    int64        `json:"size,omitempty"`
+	Details MediaDetails `json:"details,omitempty"`
+	// Maps the available streaming formats to their file extension, like "apple" to "m3u8"
+	AvailableFormats map[string]string `json:"availableFormats,omitempty"`
+	// Maps the available streaming qualities' display names to their value, like "Original" to "original"
+	AvailableQualities map[string]string `json:"availableQualities,omitempty"`
+	// URL model to generate the streaming links, like "/streaming/{format}/{quality}/{id}.{ext}"
+	ModelURL string `json:"modelUrl,omitempty"`
+	// Base URL of the streaming host
+	Host string `json:"host,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It decodes the duration in seconds.
+func (i *MediaInfos) UnmarshalJSON(data []byte) error {
+	type mediaInfos MediaInfos
+	raw := struct {
+		*mediaInfos
+		Duration jsonutil.Seconds `json:"duration,omitempty"`
+	}{mediaInfos: (*mediaInfos)(i)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.Duration = time.Duration(raw.Duration)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i MediaInfos) MarshalJSON() ([]byte, error) {
+	type mediaInfos MediaInfos
+	return json.Marshal(struct {
+		mediaInfos
+		Duration jsonutil.Seconds `json:"duration"`
+	}{mediaInfos: mediaInfos(i), Duration: jsonutil.Seconds(i.Duration)})
+}
+
+// MediaDetails contains the tracks of a media file, mapped by their track identifier (like "und1").
+type MediaDetails struct {
+	Video     map[string]VideoTrack    `json:"video,omitempty"`
+	Audio     map[string]AudioTrack    `json:"audio,omitempty"`
+	Subtitles map[string]SubtitleTrack `json:"subtitles,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// RealDebrid sometimes responds with a list instead of an object for the tracks, for example an empty list when there are no subtitles.
+// List elements are mapped by their index.
+func (d *MediaDetails) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Video     json.RawMessage `json:"video"`
+		Audio     json.RawMessage `json:"audio"`
+		Subtitles json.RawMessage `json:"subtitles"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := unmarshalTracks(raw.Video, &d.Video); err != nil {
+		return fmt.Errorf("couldn't unmarshal video tracks: %w", err)
+	}
+	if err := unmarshalTracks(raw.Audio, &d.Audio); err != nil {
+		return fmt.Errorf("couldn't unmarshal audio tracks: %w", err)
+	}
+	if err := unmarshalTracks(raw.Subtitles, &d.Subtitles); err != nil {
+		return fmt.Errorf("couldn't unmarshal subtitle tracks: %w", err)
+	}
+	return nil
+}
+
+// unmarshalTracks unmarshals a JSON object or list of tracks into the given map pointer.
+func unmarshalTracks(data json.RawMessage, v interface{}) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if data[0] == '[' {
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		// No tracks, like when the field is missing. An empty map would be omitted when marshalling.
+		if len(list) == 0 {
+			return nil
+		}
+		m := make(map[string]json.RawMessage, len(list))
+		for i, track := range list {
+			m[strconv.Itoa(i)] = track
+		}
+		var err error
+		if data, err = json.Marshal(m); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+// VideoTrack is a video track of a media file.
+type VideoTrack struct {
+	Stream string `json:"stream,omitempty"`
+	// Language in plain text, like "English"
+	Lang string `json:"lang,omitempty"`
+	// Language in ISO 639-2, like "eng"
+	LangISO    string `json:"lang_iso,omitempty"`
+	Codec      string `json:"codec,omitempty"`
+	Colorspace string `json:"colorspace,omitempty"`
+	Width      int    `json:"width,omitempty"`
+	Height     int    `json:"height,omitempty"`
+}
+
+// AudioTrack is an audio track of a media file.
+type AudioTrack struct {
+	Stream string `json:"stream,omitempty"`
+	// Language in plain text, like "English"
+	Lang string `json:"lang,omitempty"`
+	// Language in ISO 639-2, like "eng"
+	LangISO string `json:"lang_iso,omitempty"`
+	Codec   string `json:"codec,omitempty"`
+	// Sampling rate in Hz
+	Sampling int `json:"sampling,omitempty"`
+	// Number of channels, like 2.0 or 5.1
+	Channels float64 `json:"channels,omitempty"`
+}
+
+// SubtitleTrack is a subtitle track of a media file.
+type SubtitleTrack struct {
+	Stream string `json:"stream,omitempty"`
+	// Language in plain text, like "English"
+	Lang string `json:"lang,omitempty"`
+	// Language in ISO 639-2, like "eng"
+	LangISO string `json:"lang_iso,omitempty"`
+	// Subtitle format, like "ASS" or "SRT"
+	Type string `json:"type,omitempty"`
+}
+
+// DeviceCode contains the codes of RealDebrid's OAuth2 device code flow.
+type DeviceCode struct {
+	// Code for polling the credentials and getting the token
+	DeviceCode string `json:"device_code,omitempty"`
+	// Code that the user has to enter on the verification URL
+	UserCode string `json:"user_code,omitempty"`
+	// Polling interval in seconds
+	Interval int `json:"interval,omitempty"`
+	// Seconds until the codes expire
+	ExpiresIn int `json:"expires_in,omitempty"`
+	// URL where the user has to enter the user code
+	VerificationURL string `json:"verification_url,omitempty"`
+	// URL that already contains the user code
+	DirectVerificationURL string `json:"direct_verification_url,omitempty"`
+	// Point in time when the codes expire. Calculated from ExpiresIn when receiving the codes.
+	Expiry time.Time `json:"-"`
+}
+
+// Credentials are the user-specific client credentials of RealDebrid's OAuth2 device code flow.
+type Credentials struct {
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Token is an OAuth2 token.
+type Token struct {
+	AccessToken string `json:"access_token,omitempty"`
+	// Seconds until the access token expires
+	ExpiresIn int `json:"expires_in,omitempty"`
+	// "Bearer"
+	TokenType    string `json:"token_type,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Point in time when the access token expires. Calculated from ExpiresIn when receiving the token.
+	// It's (un-)marshalled, so that stored tokens keep their expiry.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// jsonDateLayouts are the layouts of RealDebrid's ISO 8601 dates ("jsonDate").
+// Depending on the endpoint, the offset is with or without a colon.
+var jsonDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999-0700",
+}
+
+// parseDate parses one of RealDebrid's ISO 8601 dates.
+func parseDate(s string) (time.Time, error) {
+	var err error
+	for _, layout := range jsonDateLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// jsonDate is one of RealDebrid's ISO 8601 dates. null, false and empty strings are decoded as zero time.Time.
+// time.Time is encoded as RFC 3339 date, so it doesn't need its own MarshalJSON.
+type jsonDate time.Time
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *jsonDate) UnmarshalJSON(data []byte) error {
+	s := jsonutil.Unquote(data)
+	if s == "" {
+		*d = jsonDate{}
+		return nil
+	}
+	t, err := parseDate(s)
+	if err != nil {
+		return fmt.Errorf("couldn't parse date %s: %w", data, err)
+	}
+	*d = jsonDate(t)
+	return nil
 }
@@ -0,0 +1,61 @@
+package realdebrid_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deflix-tv/go-debrid/realdebrid"
+)
+
+func TestUnmarshalTimes(t *testing.T) {
+	user := realdebrid.User{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"premium":86400,"expiration":"2020-11-08T13:37:00.000Z"}`), &user))
+	require.Equal(t, 1, user.ID)
+	require.Equal(t, 24*time.Hour, user.Premium)
+	require.True(t, user.Expiration.Equal(time.Date(2020, 11, 8, 13, 37, 0, 0, time.UTC)))
+
+	// Not finished yet, with a size over 2 GB
+	info := realdebrid.TorrentInfo{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"ABC","bytes":5000000000,"added":"2020-11-07T13:37:00.000Z","ended":""}`), &info))
+	require.Equal(t, int64(5000000000), info.Bytes)
+	require.True(t, info.Added.Equal(time.Date(2020, 11, 7, 13, 37, 0, 0, time.UTC)))
+	require.True(t, info.Ended.IsZero())
+
+	// Offset without colon
+	infos := realdebrid.TorrentsInfo{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"ABC","added":"2020-11-07T14:37:00+0100","ended":"2020-11-07T15:37:00+0100"}`), &infos))
+	require.True(t, infos.Added.Equal(time.Date(2020, 11, 7, 13, 37, 0, 0, time.UTC)))
+	require.True(t, infos.Ended.Equal(time.Date(2020, 11, 7, 14, 37, 0, 0, time.UTC)))
+
+	require.Error(t, json.Unmarshal([]byte(`{"added":"yesterday"}`), &info))
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tt := []struct {
+		name     string
+		json     string
+		newValue func() interface{}
+	}{
+		{"User", `{"id":1,"username":"foo","type":"premium","premium":86400.5,"expiration":"2020-11-08T13:37:00.000Z"}`, func() interface{} { return &realdebrid.User{} }},
+		{"Download", `{"id":"ABC","filename":"a.mkv","filesize":5000000000,"download":"https://a.com/a.mkv","generated":"2020-11-07T14:37:00+0100"}`, func() interface{} { return &realdebrid.Download{} }},
+		{"TorrentsInfo", `{"id":"ABC","bytes":5000000000,"status":"downloaded","added":"2020-11-07T14:37:00+0100","ended":"2020-11-07T15:37:00+0100"}`, func() interface{} { return &realdebrid.TorrentsInfo{} }},
+		{"TorrentInfo", `{"id":"ABC","bytes":5000000000,"status":"downloading","added":"2020-11-07T13:37:00.000Z","ended":"","files":[{"id":1,"path":"/a.mkv","bytes":123,"selected":1}]}`, func() interface{} { return &realdebrid.TorrentInfo{} }},
+		{"HostStatus", `{"id":"a","name":"A","status":"up","check_time":"2020-11-07T13:37:00.000Z","competitors_status":{"b.com":{"status":"down","check_time":"2020-11-07T14:37:00+0100"}}}`, func() interface{} { return &realdebrid.HostStatus{} }},
+		{"CompetitorStatus", `{"status":"down","check_time":"2020-11-07T14:37:00+0100"}`, func() interface{} { return &realdebrid.CompetitorStatus{} }},
+		{"MediaInfos", `{"filename":"a.mkv","type":"movie","duration":"5400.123","bitrate":1234,"size":5000000000,"details":{"video":{"und1":{"codec":"h264","width":1920,"height":1080}},"audio":[],"subtitles":[]}}`, func() interface{} { return &realdebrid.MediaInfos{} }},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := tc.newValue()
+			require.NoError(t, json.Unmarshal([]byte(tc.json), v))
+			data, err := json.Marshal(v)
+			require.NoError(t, err)
+			got := tc.newValue()
+			require.NoError(t, json.Unmarshal(data, got))
+			require.Equal(t, v, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package realdebrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap"
+)
+
+// CheckLink checks if a hoster link is supported and available, without unrestricting it and thus without consuming traffic.
+// The password can be empty if the link isn't password protected.
+// If the file isn't available, ErrorServiceUnavailable is returned.
+func (c *Client) CheckLink(ctx context.Context, link, password string) (LinkCheck, error) {
+	c.logger.Debug("Checking link...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("link", link)
+	if password != "" {
+		data.Set("password", password)
+	}
+	resBytes, err := c.post(ctx, c.opts.BaseURL+"/unrestrict/check", data)
+	if err != nil {
+		return LinkCheck{}, fmt.Errorf("couldn't check link: %w", err)
+	}
+	check := LinkCheck{}
+	if err = json.Unmarshal(resBytes, &check); err != nil {
+		return LinkCheck{}, fmt.Errorf("couldn't unmarshal link check: %w", err)
+	}
+
+	c.logger.Debug("Checked link", zap.String("check", fmt.Sprintf("%+v", check)), zapDebridService)
+	return check, nil
+}
+
+// UnrestrictFolder expands a hoster folder link into the links of the files in the folder.
+// The returned links can then be unrestricted with Unrestrict.
+func (c *Client) UnrestrictFolder(ctx context.Context, link string) ([]string, error) {
+	c.logger.Debug("Unrestricting folder...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("link", link)
+	resBytes, err := c.post(ctx, c.opts.BaseURL+"/unrestrict/folder", data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't unrestrict folder: %w", err)
+	}
+	links := []string{}
+	if err = json.Unmarshal(resBytes, &links); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal links: %w", err)
+	}
+
+	c.logger.Debug("Unrestricted folder", zap.Strings("links", links), zapDebridService)
+	return links, nil
+}
+
+// UnrestrictContainerFile decrypts a container file (DLC, RSDF or CCF) and returns the links it contains.
+// The returned links can then be unrestricted with Unrestrict.
+func (c *Client) UnrestrictContainerFile(ctx context.Context, container []byte) ([]string, error) {
+	c.logger.Debug("Unrestricting container file...", zapDebridService)
+
+	resBytes, err := c.put(ctx, c.opts.BaseURL+"/unrestrict/containerFile", container)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't unrestrict container file: %w", err)
+	}
+	links := []string{}
+	if err = json.Unmarshal(resBytes, &links); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal links: %w", err)
+	}
+
+	c.logger.Debug("Unrestricted container file", zap.Strings("links", links), zapDebridService)
+	return links, nil
+}
+
+// UnrestrictContainerLink decrypts a container file (DLC, RSDF or CCF) from a link and returns the links it contains.
+// The returned links can then be unrestricted with Unrestrict.
+func (c *Client) UnrestrictContainerLink(ctx context.Context, link string) ([]string, error) {
+	c.logger.Debug("Unrestricting container link...", zapDebridService)
+
+	data := url.Values{}
+	data.Set("link", link)
+	resBytes, err := c.post(ctx, c.opts.BaseURL+"/unrestrict/containerLink", data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't unrestrict container link: %w", err)
+	}
+	links := []string{}
+	if err = json.Unmarshal(resBytes, &links); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal links: %w", err)
+	}
+
+	c.logger.Debug("Unrestricted container link", zap.Strings("links", links), zapDebridService)
+	return links, nil
+}
@@ -1,21 +1,40 @@
 package realdebrid
 
-import "errors"
+import (
+	"fmt"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
 
 // SelectLargestFile returns the file ID of the largest file in the torrent.
+//
+// Deprecated: Use SelectFile with debrid.LargestFile, or a more specific selector like debrid.DefaultFileSelector.
 func SelectLargestFile(info TorrentInfo) (File, error) {
-	var largestFile File
-	largestSize := 0
+	return SelectFile(info, debrid.LargestFile)
+}
 
-	for _, file := range info.Files {
-		if file.Bytes > largestSize {
-			largestFile = file
-			largestSize = file.Bytes
-		}
+// SelectFile returns the file in the torrent that the selector selects.
+func SelectFile(info TorrentInfo, selector debrid.FileSelector) (File, error) {
+	files := make([]debrid.File, len(info.Files))
+	for i, file := range info.Files {
+		files[i] = debrid.File{Path: file.Path, Size: file.Bytes}
 	}
-	if largestSize == 0 {
-		return File{}, errors.New("couldn't find largest file in torrent info")
+	i, err := selector.SelectFile(files)
+	if err != nil {
+		return File{}, fmt.Errorf("couldn't select file in torrent info: %w", err)
 	}
+	return info.Files[i], nil
+}
 
-	return largestFile, nil
+// SelectVariant returns the instant availability variant that contains the file with the given ID.
+// If multiple variants contain the file, the one with the fewest files is returned, because its files need to be selected together for an instant download.
+// The boolean return value signals whether a variant was found.
+func SelectVariant(variants []InstantAvailability, fileID int) (InstantAvailability, bool) {
+	var result InstantAvailability
+	for _, variant := range variants {
+		if _, found := variant[fileID]; found && (result == nil || len(variant) < len(result)) {
+			result = variant
+		}
+	}
+	return result, result != nil
 }
@@ -0,0 +1,327 @@
+package debrid
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Release contains the info that's parsed from a release name (torrent name or file name) by ParseRelease.
+// Empty fields mean that the info wasn't found in the name.
+// The values are normalized, for example "x264", "H264" and "AVC" all lead to the codec "H.264".
+type Release struct {
+	// Title with separators replaced by spaces, for example "Night of the Living Dead"
+	Title string
+	// Release year, 0 if unknown
+	Year int
+	// "2160p", "1080p", "720p", "576p", "480p" or "360p". "4K" and "UHD" lead to "2160p".
+	Resolution string
+	// "Remux", "BluRay", "WEB-DL", "WEBRip", "HDTV", "DVDRip", "Screener", "TeleSync" or "CAM"
+	Source string
+	// "H.265", "H.264", "AV1", "VP9", "XviD" or "DivX"
+	Codec string
+	// Any of "DV" (Dolby Vision), "HDR10+", "HDR10", "HDR" and "HLG"
+	HDR []string
+	// Any of "Atmos", "TrueHD", "DTS-HD MA", "DTS-HD", "DTS", "DD+", "DD", "AAC", "FLAC", "MP3" and "Opus"
+	Audio []string
+	// Any of "Multi", "Dual", "English", "French", "German", "Italian", "Spanish", "Latino", "Portuguese", "Russian", "Hindi", "Japanese", "Korean" and "Chinese"
+	Languages []string
+	// Release group
+	Group string
+}
+
+type releaseRule struct {
+	regex *regexp.Regexp
+	value string
+}
+
+// newReleaseRule creates a rule that matches the pattern as separate word(s), case-insensitive.
+func newReleaseRule(pattern, value string) releaseRule {
+	return releaseRule{
+		regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:` + pattern + `)(?:[^a-z0-9]|$)`),
+		value: value,
+	}
+}
+
+// The rules are checked in order, so more specific ones must come first.
+var (
+	resolutionRules = []releaseRule{
+		newReleaseRule(`2160p|4k|uhd`, "2160p"),
+		newReleaseRule(`1080[pi]`, "1080p"),
+		newReleaseRule(`720p`, "720p"),
+		newReleaseRule(`576[pi]`, "576p"),
+		newReleaseRule(`480[pi]`, "480p"),
+		newReleaseRule(`360p`, "360p"),
+	}
+	sourceRules = []releaseRule{
+		newReleaseRule(`remux|bdremux`, "Remux"),
+		newReleaseRule(`blu-?ray|bdrip|brrip|bdmv|bd25|bd50`, "BluRay"),
+		newReleaseRule(`web-?dl|webdl`, "WEB-DL"),
+		newReleaseRule(`web-?rip|web`, "WEBRip"),
+		newReleaseRule(`hdtv|pdtv|hdtvrip`, "HDTV"),
+		newReleaseRule(`dvd-?rip|dvd|dvd-?r|dvd5|dvd9`, "DVDRip"),
+		newReleaseRule(`scr|screener|dvdscr|bdscr`, "Screener"),
+		newReleaseRule(`ts|telesync|hdts|tc|telecine`, "TeleSync"),
+		newReleaseRule(`cam|camrip|hdcam`, "CAM"),
+	}
+	codecRules = []releaseRule{
+		newReleaseRule(`[xh][ .]?265|hevc`, "H.265"),
+		newReleaseRule(`[xh][ .]?264|avc`, "H.264"),
+		newReleaseRule(`av1`, "AV1"),
+		newReleaseRule(`vp9`, "VP9"),
+		newReleaseRule(`xvid`, "XviD"),
+		newReleaseRule(`divx`, "DivX"),
+	}
+	hdrRules = []releaseRule{
+		newReleaseRule(`dv|dovi|dolby[ .]?vision`, "DV"),
+		newReleaseRule(`hdr10(?:\+|plus)`, "HDR10+"),
+		newReleaseRule(`hdr10`, "HDR10"),
+		newReleaseRule(`hdr`, "HDR"),
+		newReleaseRule(`hlg`, "HLG"),
+	}
+	audioRules = []releaseRule{
+		newReleaseRule(`atmos`, "Atmos"),
+		newReleaseRule(`true-?hd`, "TrueHD"),
+		newReleaseRule(`dts-?hd[ .-]?ma|dts-?ma`, "DTS-HD MA"),
+		newReleaseRule(`dts-?hd`, "DTS-HD"),
+		newReleaseRule(`dts`, "DTS"),
+		newReleaseRule(`ddp|dd\+|e-?ac-?3|ddp[257]\.?[01]`, "DD+"),
+		newReleaseRule(`dd|ac-?3|dd[257]\.?[01]`, "DD"),
+		newReleaseRule(`aac(?:[257]\.?[01])?`, "AAC"),
+		newReleaseRule(`flac`, "FLAC"),
+		newReleaseRule(`mp3`, "MP3"),
+		newReleaseRule(`opus`, "Opus"),
+	}
+	languageRules = []releaseRule{
+		newReleaseRule(`multi|multi-?subs?|multi-?lang`, "Multi"),
+		newReleaseRule(`dual|dual-?audio`, "Dual"),
+		newReleaseRule(`english|eng`, "English"),
+		newReleaseRule(`french|truefrench|vff|vfq|vf2`, "French"),
+		newReleaseRule(`german|deutsch|ger`, "German"),
+		newReleaseRule(`italian|ita`, "Italian"),
+		newReleaseRule(`spanish|castellano`, "Spanish"),
+		newReleaseRule(`latino`, "Latino"),
+		newReleaseRule(`portuguese|pt-?br`, "Portuguese"),
+		newReleaseRule(`russian|rus`, "Russian"),
+		newReleaseRule(`hindi`, "Hindi"),
+		newReleaseRule(`japanese|jpn`, "Japanese"),
+		newReleaseRule(`korean|kor`, "Korean"),
+		newReleaseRule(`chinese|mandarin|cantonese`, "Chinese"),
+	}
+
+	yearRegex     = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])((?:19|20)\d\d)(?:[^a-z0-9]|$)`)
+	groupRegex    = regexp.MustCompile(`-([a-zA-Z0-9]+)$`)
+	animeRegex    = regexp.MustCompile(`^\[([^\]]+)\]`)
+	bracketRegex  = regexp.MustCompile(`[ ._-]*\[([^\]]+)\]$`)
+	parensRegex   = regexp.MustCompile(`[ ._-]*\([^)]*\)$`)
+	otherExtRegex = regexp.MustCompile(`(?i)\.(?:iso|img|nfo|txt|srt|sub|idx|ass|ssa|jpe?g|png|rar|zip|7z)$`)
+	// Separators for the title
+	titleSepRegex = regexp.MustCompile(`[._]+`)
+)
+
+// ParseRelease parses a release name, like a torrent name or the name or path of a file in a torrent.
+// It can be used for example with realdebrid.AvailableFile.Filename and premiumize.CachedFile.Filename.
+func ParseRelease(name string) Release {
+	name = path.Base(name)
+	// Only strip known file extensions, not for example ".1" of "DDP5.1" or ".x265" of a torrent name.
+	if IsVideo(File{Path: name}) || otherExtRegex.MatchString(name) {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+
+	r := Release{}
+	r.Resolution = firstMatch(name, resolutionRules)
+	r.Source = firstMatch(name, sourceRules)
+	r.Codec = firstMatch(name, codecRules)
+	r.HDR = allMatches(name, hdrRules)
+	r.Audio = allMatches(name, audioRules)
+	r.Languages = allMatches(name, languageRules)
+
+	// Title and year: The title is everything before the year, or before the first technical info.
+	titleEnd := len(name)
+	if locs := yearRegex.FindAllStringSubmatchIndex(name, -1); locs != nil {
+		// Use the last year, so that titles like "2001: A Space Odyssey (1968)" work
+		loc := locs[len(locs)-1]
+		// Unless it's at the very beginning, then it's part of the title
+		if loc[2] == 0 && len(locs) > 1 {
+			loc = locs[len(locs)-2]
+		}
+		if loc[2] > 0 {
+			r.Year, _ = strconv.Atoi(name[loc[2]:loc[3]])
+			titleEnd = loc[2]
+		}
+	}
+	for _, rules := range [][]releaseRule{resolutionRules, sourceRules, codecRules} {
+		for _, rule := range rules {
+			if loc := rule.regex.FindStringIndex(name); loc != nil && loc[0] < titleEnd {
+				titleEnd = loc[0]
+			}
+		}
+	}
+	if loc := seasonEpisodeRegex.FindStringIndex(name); loc != nil && loc[0] < titleEnd {
+		titleEnd = loc[0]
+	}
+
+	// Group, either at the beginning like "[Group]" for anime, at the end like "-GROUP", or at the end like "[GROUP]"
+	var bracketGroup string
+	stripped := parensRegex.ReplaceAllString(name, "")
+	if m := bracketRegex.FindStringSubmatch(stripped); m != nil {
+		bracketGroup = m[1]
+		stripped = strings.TrimSuffix(stripped, m[0])
+	}
+	if m := animeRegex.FindStringSubmatch(name); m != nil {
+		r.Group = m[1]
+	} else if m := groupRegex.FindStringSubmatch(stripped); m != nil && !strings.EqualFold(m[1], "DL") && !strings.EqualFold(m[1], "Rip") {
+		r.Group = m[1]
+	} else if bracketGroup != "" && firstMatch(bracketGroup, resolutionRules) == "" && firstMatch(bracketGroup, sourceRules) == "" && firstMatch(bracketGroup, codecRules) == "" {
+		r.Group = bracketGroup
+	}
+
+	title := name[:titleEnd]
+	if m := animeRegex.FindStringIndex(title); m != nil {
+		title = title[m[1]:]
+	}
+	title = titleSepRegex.ReplaceAllString(title, " ")
+	r.Title = strings.Trim(title, " -([")
+
+	return r
+}
+
+func firstMatch(name string, rules []releaseRule) string {
+	for _, rule := range rules {
+		if rule.regex.MatchString(name) {
+			return rule.value
+		}
+	}
+	return ""
+}
+
+// allMatches returns the values of all matching rules.
+// Matched parts of the name are blanked, so that less specific rules don't match the same part again, like "DTS" in "DTS-HD".
+func allMatches(name string, rules []releaseRule) []string {
+	var result []string
+	for _, rule := range rules {
+		if rule.regex.MatchString(name) {
+			result = append(result, rule.value)
+			name = rule.regex.ReplaceAllStringFunc(name, func(match string) string {
+				return strings.Repeat(" ", len(match))
+			})
+		}
+	}
+	return result
+}
+
+// ReleaseScorer scores releases based on the parsed release info.
+// The score is the sum of the values for each of the release's properties. Missing map entries count as 0.
+// The keys must be the normalized values as documented in Release.
+type ReleaseScorer struct {
+	Resolutions map[string]int
+	Sources     map[string]int
+	Codecs      map[string]int
+	// Each matching HDR format is counted
+	HDR map[string]int
+	// Each matching audio format is counted
+	Audio map[string]int
+	// Each matching language is counted
+	Languages map[string]int
+	// Case-insensitive release group names
+	Groups map[string]int
+}
+
+var _ FileSelector = ReleaseScorer{}
+
+// DefaultReleaseScorer is a ReleaseScorer with reasonable default values.
+// It prefers higher resolutions and better sources, and strongly penalizes cam and telesync releases.
+var DefaultReleaseScorer = ReleaseScorer{
+	Resolutions: map[string]int{
+		"2160p": 40,
+		"1080p": 30,
+		"720p":  20,
+		"576p":  10,
+		"480p":  10,
+		"360p":  5,
+	},
+	Sources: map[string]int{
+		"Remux":    25,
+		"BluRay":   20,
+		"WEB-DL":   18,
+		"WEBRip":   15,
+		"HDTV":     10,
+		"DVDRip":   8,
+		"Screener": -20,
+		"TeleSync": -40,
+		"CAM":      -50,
+	},
+	Codecs: map[string]int{
+		"H.265": 5,
+		"AV1":   4,
+		"H.264": 3,
+		"XviD":  -5,
+		"DivX":  -5,
+	},
+	HDR: map[string]int{
+		"DV":     5,
+		"HDR10+": 5,
+		"HDR10":  4,
+		"HDR":    3,
+	},
+	Audio: map[string]int{
+		"Atmos":     3,
+		"TrueHD":    3,
+		"DTS-HD MA": 3,
+		"DTS-HD":    2,
+		"DTS":       2,
+		"DD+":       2,
+		"DD":        1,
+		"AAC":       1,
+	},
+}
+
+// Score returns the score of a release. Higher is better.
+func (s ReleaseScorer) Score(r Release) int {
+	score := s.Resolutions[r.Resolution] + s.Sources[r.Source] + s.Codecs[r.Codec]
+	for _, hdr := range r.HDR {
+		score += s.HDR[hdr]
+	}
+	for _, audio := range r.Audio {
+		score += s.Audio[audio]
+	}
+	for _, lang := range r.Languages {
+		score += s.Languages[lang]
+	}
+	for group, groupScore := range s.Groups {
+		if strings.EqualFold(group, r.Group) {
+			score += groupScore
+		}
+	}
+	return score
+}
+
+// Rank returns the indexes of the files, ordered by the score of their release info (best first).
+// Files with the same score are ordered by size (largest first), and then by their original order.
+// This can be used to rank the available files of multiple debrid services, after converting them to File.
+func (s ReleaseScorer) Rank(files []File) []int {
+	scores := make([]int, len(files))
+	indexes := make([]int, len(files))
+	for i, file := range files {
+		scores[i] = s.Score(ParseRelease(file.Path))
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		ia, ib := indexes[a], indexes[b]
+		if scores[ia] != scores[ib] {
+			return scores[ia] > scores[ib]
+		}
+		return files[ia].Size > files[ib].Size
+	})
+	return indexes
+}
+
+// SelectFile selects the file with the best score, so a ReleaseScorer can be used as FileSelector.
+func (s ReleaseScorer) SelectFile(files []File) (int, error) {
+	if len(files) == 0 {
+		return -1, ErrorNoMatchingFile
+	}
+	return s.Rank(files)[0], nil
+}
@@ -0,0 +1,70 @@
+package debrid_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
+
+func TestParseRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected debrid.Release
+	}{
+		{
+			"Night.Of.The.Living.Dead.1968.720p.BluRay.x264-[YTS.AM].mp4",
+			debrid.Release{Title: "Night Of The Living Dead", Year: 1968, Resolution: "720p", Source: "BluRay", Codec: "H.264", Group: "YTS.AM"},
+		},
+		{
+			"Movie.2020.2160p.WEB-DL.DDP5.1.Atmos.DV.HDR10+.H.265-GROUP",
+			debrid.Release{Title: "Movie", Year: 2020, Resolution: "2160p", Source: "WEB-DL", Codec: "H.265", HDR: []string{"DV", "HDR10+"}, Audio: []string{"Atmos", "DD+"}, Group: "GROUP"},
+		},
+		{
+			"/Movie (2019)/Movie.2019.1080p.BluRay.REMUX.AVC.DTS-HD.MA.5.1.FRENCH-GRP.mkv",
+			debrid.Release{Title: "Movie", Year: 2019, Resolution: "1080p", Source: "Remux", Codec: "H.264", Audio: []string{"DTS-HD MA"}, Languages: []string{"French"}, Group: "GRP"},
+		},
+		{
+			"2001.A.Space.Odyssey.1968.1080p.BluRay.x265",
+			debrid.Release{Title: "2001 A Space Odyssey", Year: 1968, Resolution: "1080p", Source: "BluRay", Codec: "H.265"},
+		},
+		{
+			"[SubsPlease] Show - 07 (1080p) [ABCD1234].mkv",
+			debrid.Release{Title: "Show - 07", Resolution: "1080p", Group: "SubsPlease"},
+		},
+		{
+			"Show.S02E07.720p.HDTV.x264-GROUP",
+			debrid.Release{Title: "Show", Resolution: "720p", Source: "HDTV", Codec: "H.264", Group: "GROUP"},
+		},
+		{
+			"Movie 2021 HDCAM",
+			debrid.Release{Title: "Movie", Year: 2021, Source: "CAM"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, debrid.ParseRelease(test.name))
+		})
+	}
+}
+
+func TestReleaseScorer(t *testing.T) {
+	files := []debrid.File{
+		{Path: "Movie.2020.720p.WEBRip.x264-GRP.mkv", Size: 1000},
+		{Path: "Movie.2020.HDCAM.x264.mkv", Size: 3000},
+		{Path: "Movie.2020.1080p.BluRay.x264-GRP.mkv", Size: 2000},
+		{Path: "Movie.2020.1080p.BluRay.x264-OTHER.mkv", Size: 2500},
+	}
+	require.Equal(t, []int{3, 2, 0, 1}, debrid.DefaultReleaseScorer.Rank(files))
+
+	// Configured preference for a group
+	scorer := debrid.DefaultReleaseScorer
+	scorer.Groups = map[string]int{"grp": 10}
+	i, err := scorer.SelectFile(files)
+	require.NoError(t, err)
+	require.Equal(t, 2, i)
+
+	_, err = scorer.SelectFile(nil)
+	require.ErrorIs(t, err, debrid.ErrorNoMatchingFile)
+}
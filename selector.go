@@ -0,0 +1,180 @@
+package debrid
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrorNoMatchingFile is returned by a FileSelector when none of the files match its criteria.
+var ErrorNoMatchingFile = errors.New("no matching file")
+
+// File is a file in a torrent or other multi-file download, in a form that's independent of the debrid service.
+// The service-specific packages convert their file types to this one for selecting a file.
+type File struct {
+	// Path to the file inside the torrent, or only the file name if the debrid service doesn't provide the full path
+	Path string
+	// Size in bytes
+	Size int64
+}
+
+// FileSelector selects the file to use (for example to stream) from a list of files.
+type FileSelector interface {
+	// SelectFile returns the index of the selected file.
+	// It returns an error wrapping ErrorNoMatchingFile if no file could be selected.
+	SelectFile(files []File) (int, error)
+}
+
+// FileSelectorFunc is an adapter to allow the use of ordinary functions as FileSelector.
+type FileSelectorFunc func(files []File) (int, error)
+
+// SelectFile calls f(files).
+func (f FileSelectorFunc) SelectFile(files []File) (int, error) {
+	return f(files)
+}
+
+// FileFilter reports whether a file should be considered by a FileSelector.
+type FileFilter func(file File) bool
+
+var (
+	_ FileSelector = FileSelectorFunc(nil)
+
+	// LargestFile selects the largest file.
+	// This is the strategy that the clients used before file selectors were introduced.
+	LargestFile FileSelector = FileSelectorFunc(selectLargestFile)
+
+	// DefaultFileSelector selects the largest video file that's not a sample or trailer,
+	// and falls back to the largest file if there's no such video file.
+	DefaultFileSelector = FirstOf(
+		Filtered(LargestFile, IsVideo, IsNotSample),
+		LargestFile,
+	)
+)
+
+func selectLargestFile(files []File) (int, error) {
+	index := -1
+	var largestSize int64
+	for i, file := range files {
+		if file.Size > largestSize {
+			index = i
+			largestSize = file.Size
+		}
+	}
+	if index == -1 {
+		return -1, ErrorNoMatchingFile
+	}
+	return index, nil
+}
+
+// Filtered returns a FileSelector that only passes the files that match all filters to the given selector.
+// The returned index refers to the original, unfiltered list of files.
+func Filtered(selector FileSelector, filters ...FileFilter) FileSelector {
+	return FileSelectorFunc(func(files []File) (int, error) {
+		var filteredFiles []File
+		var originalIndexes []int
+	Files:
+		for i, file := range files {
+			for _, filter := range filters {
+				if !filter(file) {
+					continue Files
+				}
+			}
+			filteredFiles = append(filteredFiles, file)
+			originalIndexes = append(originalIndexes, i)
+		}
+		if len(filteredFiles) == 0 {
+			return -1, ErrorNoMatchingFile
+		}
+		i, err := selector.SelectFile(filteredFiles)
+		if err != nil {
+			return -1, err
+		}
+		return originalIndexes[i], nil
+	})
+}
+
+// FirstOf returns a FileSelector that tries the given selectors in order and returns the first successful selection.
+func FirstOf(selectors ...FileSelector) FileSelector {
+	return FileSelectorFunc(func(files []File) (int, error) {
+		for _, selector := range selectors {
+			i, err := selector.SelectFile(files)
+			if err == nil {
+				return i, nil
+			} else if !errors.Is(err, ErrorNoMatchingFile) {
+				return -1, err
+			}
+		}
+		return -1, ErrorNoMatchingFile
+	})
+}
+
+// VideoExtensions are the file extensions that IsVideo regards as video files.
+var VideoExtensions = []string{
+	".3gp", ".avi", ".divx", ".flv", ".m2ts", ".m4v", ".mkv", ".mov", ".mp4", ".mpeg", ".mpg", ".mts", ".ogm", ".ts", ".vob", ".webm", ".wmv",
+}
+
+// IsVideo is a FileFilter that matches files with one of the VideoExtensions.
+func IsVideo(file File) bool {
+	ext := strings.ToLower(path.Ext(file.Path))
+	for _, videoExt := range VideoExtensions {
+		if ext == videoExt {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleWords are the words that indicate samples, trailers and other bonus material.
+const sampleWords = `samples?|trailers?|teasers?|featurettes?|behind[^a-z0-9]?the[^a-z0-9]?scenes|deleted[^a-z0-9]?scenes?|extras`
+
+var (
+	// Matches file names (without extension) that end with one of the sampleWords, like "movie-sample", or that start with "sample", like "sample-movie".
+	// Titles that merely contain one of the words, like "Trailer.Park.Boys.S01E01", don't match.
+	sampleNameRegex = regexp.MustCompile(`(?i)((^|[^a-z0-9])(` + sampleWords + `)$|^samples?([^a-z0-9]|$))`)
+	// Matches folder names that consist of only one of the sampleWords, like "Sample" or "Behind the Scenes".
+	sampleFolderRegex = regexp.MustCompile(`(?i)^(` + sampleWords + `)$`)
+)
+
+// IsNotSample is a FileFilter that excludes samples, trailers, featurettes and other bonus material.
+// It checks the file name and the names of the folders that the file is in, so that the torrent's folder and title don't lead to false positives.
+func IsNotSample(file File) bool {
+	dir, name := path.Split(file.Path)
+	if sampleNameRegex.MatchString(strings.TrimSuffix(name, path.Ext(name))) {
+		return false
+	}
+	for _, folder := range strings.Split(dir, "/") {
+		if sampleFolderRegex.MatchString(folder) {
+			return false
+		}
+	}
+	return true
+}
+
+// MinSize returns a FileFilter that matches files with at least the given size in bytes.
+func MinSize(bytes int64) FileFilter {
+	return func(file File) bool {
+		return file.Size >= bytes
+	}
+}
+
+// MatchRegexp returns a FileFilter that matches files whose path matches the regular expression.
+func MatchRegexp(re *regexp.Regexp) FileFilter {
+	return func(file File) bool {
+		return re.MatchString(file.Path)
+	}
+}
+
+// MatchGlob returns a FileFilter that matches files via a glob pattern as supported by path.Match.
+// If the pattern contains a "/", it's matched against the full path, otherwise only against the file name.
+// Invalid patterns don't match any file.
+func MatchGlob(pattern string) FileFilter {
+	return func(file File) bool {
+		name := file.Path
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(name)
+		}
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}
+}
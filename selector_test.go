@@ -0,0 +1,66 @@
+package debrid_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
+
+var bonusDiscFiles = []debrid.File{
+	{Path: "/Movie.2020.1080p.BluRay/Extras/Behind.The.Scenes.mkv", Size: 4000},
+	{Path: "/Movie.2020.1080p.BluRay/Movie.2020.1080p.BluRay.mkv", Size: 3000},
+	{Path: "/Movie.2020.1080p.BluRay/Movie.2020.1080p.BluRay.nfo", Size: 5},
+	{Path: "/Movie.2020.1080p.BluRay/Movie.2020.1080p.BluRay.sample.mkv", Size: 50},
+	{Path: "/Movie.2020.1080p.BluRay/Movie.2020.1080p.BluRay.iso", Size: 9000},
+}
+
+func TestFileSelectors(t *testing.T) {
+	i, err := debrid.LargestFile.SelectFile(bonusDiscFiles)
+	require.NoError(t, err)
+	require.Equal(t, 4, i)
+
+	i, err = debrid.DefaultFileSelector.SelectFile(bonusDiscFiles)
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+
+	// Fallback to largest file if there's no video
+	i, err = debrid.DefaultFileSelector.SelectFile(bonusDiscFiles[2:3])
+	require.NoError(t, err)
+	require.Equal(t, 0, i)
+
+	_, err = debrid.DefaultFileSelector.SelectFile(nil)
+	require.ErrorIs(t, err, debrid.ErrorNoMatchingFile)
+
+	// Composition
+	selector := debrid.Filtered(debrid.LargestFile, debrid.MatchGlob("*.mkv"), debrid.MinSize(100))
+	i, err = selector.SelectFile(bonusDiscFiles)
+	require.NoError(t, err)
+	require.Equal(t, 0, i)
+
+	selector = debrid.Filtered(debrid.LargestFile, debrid.MatchRegexp(regexp.MustCompile(`(?i)sample`)))
+	i, err = selector.SelectFile(bonusDiscFiles)
+	require.NoError(t, err)
+	require.Equal(t, 3, i)
+
+	selector = debrid.Filtered(debrid.LargestFile, debrid.MinSize(10000))
+	_, err = selector.SelectFile(bonusDiscFiles)
+	require.ErrorIs(t, err, debrid.ErrorNoMatchingFile)
+}
+
+func TestIsNotSample(t *testing.T) {
+	require.True(t, debrid.IsNotSample(debrid.File{Path: "/Samplers.2019.mkv"}))
+	require.True(t, debrid.IsNotSample(debrid.File{Path: "/Extraction.2020.mkv"}))
+	require.False(t, debrid.IsNotSample(debrid.File{Path: "/Sample/movie.mkv"}))
+	require.False(t, debrid.IsNotSample(debrid.File{Path: "/movie-trailer.mp4"}))
+	require.False(t, debrid.IsNotSample(debrid.File{Path: "/Featurettes/Interview.mkv"}))
+	require.False(t, debrid.IsNotSample(debrid.File{Path: "/Movie.2020/Behind the Scenes/Interview.mkv"}))
+	require.False(t, debrid.IsNotSample(debrid.File{Path: "/sample-movie.2020.mkv"}))
+	// Titles that contain one of the words aren't samples
+	require.True(t, debrid.IsNotSample(debrid.File{Path: "/Trailer.Park.Boys.S01E01.mkv"}))
+	require.True(t, debrid.IsNotSample(debrid.File{Path: "/Trailer.Park.Boys.S01/Trailer.Park.Boys.S01E01.mkv"}))
+	require.True(t, debrid.IsNotSample(debrid.File{Path: "/Extras.S01.1080p/Extras.S01E01.mkv"}))
+	require.True(t, debrid.IsNotSample(debrid.File{Path: "/The.Extras.2020/movie.mkv"}))
+}
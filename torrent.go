@@ -0,0 +1,120 @@
+package debrid
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorInvalidTorrent is returned when bencoded data isn't a valid .torrent file.
+var ErrorInvalidTorrent = errors.New("invalid torrent")
+
+// Torrent contains the info from a .torrent file that's relevant for debrid services.
+type Torrent struct {
+	// Normalized info hash, see NormalizeInfoHash
+	InfoHash string
+	// Suggested name of the file (single-file torrent) or directory (multi-file torrent)
+	Name string
+	// Files in the torrent. A single-file torrent has exactly one file.
+	Files []TorrentFile
+	// Tracker URLs from "announce" and "announce-list", without duplicates
+	Trackers []string
+}
+
+// TorrentFile represents a file in a torrent.
+type TorrentFile struct {
+	// Path to the file inside the torrent, starting with "/", like in RealDebrid's torrent info.
+	// For a multi-file torrent the torrent name (directory) isn't part of the path.
+	Path string
+	// Size in bytes
+	Length int64
+}
+
+// ParseTorrent decodes the content of a .torrent file and calculates its info hash.
+func ParseTorrent(data []byte) (Torrent, error) {
+	v, d, err := decodeBencode(data)
+	if err != nil {
+		return Torrent{}, err
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return Torrent{}, fmt.Errorf("%w: root is not a dictionary", ErrorInvalidTorrent)
+	}
+	info, ok := root["info"].(map[string]interface{})
+	if !ok {
+		return Torrent{}, fmt.Errorf("%w: missing info dictionary", ErrorInvalidTorrent)
+	}
+
+	hash := sha1.Sum(data[d.infoStart:d.infoEnd])
+	t := Torrent{
+		InfoHash: strings.ToUpper(hex.EncodeToString(hash[:])),
+	}
+	t.Name, _ = info["name"].(string)
+
+	if length, ok := info["length"].(int64); ok {
+		t.Files = []TorrentFile{{Path: "/" + t.Name, Length: length}}
+	} else if files, ok := info["files"].([]interface{}); ok {
+		for i, f := range files {
+			file, ok := f.(map[string]interface{})
+			if !ok {
+				return Torrent{}, fmt.Errorf("%w: file %v is not a dictionary", ErrorInvalidTorrent, i)
+			}
+			length, _ := file["length"].(int64)
+			pathElems, _ := file["path"].([]interface{})
+			var path string
+			for _, pathElem := range pathElems {
+				s, _ := pathElem.(string)
+				path += "/" + s
+			}
+			if path == "" {
+				return Torrent{}, fmt.Errorf("%w: file %v has no path", ErrorInvalidTorrent, i)
+			}
+			t.Files = append(t.Files, TorrentFile{Path: path, Length: length})
+		}
+	} else {
+		return Torrent{}, fmt.Errorf("%w: info dictionary has neither length nor files", ErrorInvalidTorrent)
+	}
+
+	seen := map[string]struct{}{}
+	addTracker := func(v interface{}) {
+		if tracker, ok := v.(string); ok && tracker != "" {
+			if _, found := seen[tracker]; !found {
+				seen[tracker] = struct{}{}
+				t.Trackers = append(t.Trackers, tracker)
+			}
+		}
+	}
+	addTracker(root["announce"])
+	if tiers, ok := root["announce-list"].([]interface{}); ok {
+		for _, tier := range tiers {
+			if trackers, ok := tier.([]interface{}); ok {
+				for _, tracker := range trackers {
+					addTracker(tracker)
+				}
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// Size returns the sum of all file sizes in bytes.
+func (t Torrent) Size() int64 {
+	var size int64
+	for _, f := range t.Files {
+		size += f.Length
+	}
+	return size
+}
+
+// Magnet returns a Magnet for the torrent, for example to use it with a debrid service that only accepts magnet URIs.
+func (t Torrent) Magnet() Magnet {
+	return Magnet{
+		InfoHash: t.InfoHash,
+		Name:     t.Name,
+		Trackers: t.Trackers,
+		Length:   t.Size(),
+	}
+}
@@ -0,0 +1,55 @@
+package debrid_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	debrid "github.com/deflix-tv/go-debrid"
+)
+
+func TestParseTorrent(t *testing.T) {
+	// Single file
+	info := "d6:lengthi828760756e4:name59:Night.Of.The.Living.Dead.1968.720p.BluRay.x264-[YTS.AM].mp412:piece lengthi524288e6:pieces0:e"
+	data := "d8:announce42:udp://tracker.opentrackr.org:1337/announce4:info" + info + "e"
+	expectedHash := sha1.Sum([]byte(info))
+
+	torrent, err := debrid.ParseTorrent([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, strings.ToUpper(hex.EncodeToString(expectedHash[:])), torrent.InfoHash)
+	require.Equal(t, "Night.Of.The.Living.Dead.1968.720p.BluRay.x264-[YTS.AM].mp4", torrent.Name)
+	require.Equal(t, []debrid.TorrentFile{{Path: "/Night.Of.The.Living.Dead.1968.720p.BluRay.x264-[YTS.AM].mp4", Length: 828760756}}, torrent.Files)
+	require.Equal(t, []string{"udp://tracker.opentrackr.org:1337/announce"}, torrent.Trackers)
+
+	// Multi file, with duplicate tracker in announce-list
+	info = "d5:filesld6:lengthi828760756e4:pathl9:movie.mp4eed6:lengthi58132e4:pathl6:extras10:poster.jpgeee4:name5:Movie12:piece lengthi524288e6:pieces0:e"
+	data = "d8:announce11:udp://a:1/a13:announce-listll11:udp://a:1/aee4:info" + info + "e"
+	expectedHash = sha1.Sum([]byte(info))
+
+	torrent, err = debrid.ParseTorrent([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, strings.ToUpper(hex.EncodeToString(expectedHash[:])), torrent.InfoHash)
+	require.Equal(t, "Movie", torrent.Name)
+	require.Equal(t, []debrid.TorrentFile{
+		{Path: "/movie.mp4", Length: 828760756},
+		{Path: "/extras/poster.jpg", Length: 58132},
+	}, torrent.Files)
+	require.Equal(t, []string{"udp://a:1/a"}, torrent.Trackers)
+	require.Equal(t, int64(828818888), torrent.Size())
+	require.Equal(t, torrent.InfoHash, torrent.Magnet().InfoHash)
+
+	// Invalid
+	_, err = debrid.ParseTorrent([]byte("d4:infoi1ee"))
+	require.ErrorIs(t, err, debrid.ErrorInvalidTorrent)
+	_, err = debrid.ParseTorrent([]byte("d4:info"))
+	require.ErrorIs(t, err, debrid.ErrorInvalidBencode)
+	_, err = debrid.ParseTorrent([]byte("d3:foo99:bare"))
+	require.ErrorIs(t, err, debrid.ErrorInvalidBencode)
+	// Deeply nested data is rejected instead of overflowing the stack
+	nested := "d4:info" + strings.Repeat("l", 100000) + strings.Repeat("e", 100000) + "e"
+	_, err = debrid.ParseTorrent([]byte(nested))
+	require.ErrorIs(t, err, debrid.ErrorInvalidBencode)
+}